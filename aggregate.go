@@ -0,0 +1,130 @@
+package mcts
+
+import (
+	"math"
+	"sort"
+)
+
+type ScoreStrategy string
+
+const (
+	// Min is the lowest score.
+	Min ScoreStrategy = "min"
+	// Max is the highest score.
+	Max ScoreStrategy = "max"
+	// Avg is the mean of the scores.
+	Avg ScoreStrategy = "avg"
+	// Median is the middle score, or the mean of both middle scores.
+	Median ScoreStrategy = "median"
+	// TrimmedMean is the mean after dropping the SimulationConfig.Trim lowest and highest scores.
+	TrimmedMean ScoreStrategy = "trimmed"
+	// Quantile is the SimulationConfig.Level quantile of the scores, interpolated between ranks.
+	Quantile ScoreStrategy = "quantile"
+	// CVaR is the mean of the worst SimulationConfig.Level fraction of the scores,
+	// a risk-averse aggregation.
+	CVaR ScoreStrategy = "cvar"
+	// Custom aggregates the scores with SimulationConfig.Aggregate.
+	Custom ScoreStrategy = "custom"
+)
+
+const (
+	defaultTrim  = 0.1
+	defaultLevel = 0.1
+)
+
+func (s SimulationConfig) runs() int {
+	if s.Ratio < 1 {
+		return 1
+	}
+	return s.Ratio
+}
+
+func (s SimulationConfig) aggregate(scores []float64) float64 {
+	switch s.Strategy {
+	case Min:
+		return minScore(scores)
+	case Max:
+		return maxScore(scores)
+	case Median:
+		return quantile(scores, 0.5)
+	case TrimmedMean:
+		return trimmedMean(scores, orDefault(s.Trim, defaultTrim))
+	case Quantile:
+		return quantile(scores, orDefault(s.Level, defaultLevel))
+	case CVaR:
+		return cvar(scores, orDefault(s.Level, defaultLevel))
+	case Custom:
+		if s.Aggregate != nil {
+			return s.Aggregate(scores)
+		}
+	}
+	return mean(scores)
+}
+
+func orDefault(v, def float64) float64 {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+func mean(scores []float64) float64 {
+	total := 0.0
+	for _, score := range scores {
+		total += score
+	}
+	return total / float64(len(scores))
+}
+
+func minScore(scores []float64) float64 {
+	min := scores[0]
+	for _, score := range scores[1:] {
+		min = math.Min(min, score)
+	}
+	return min
+}
+
+func maxScore(scores []float64) float64 {
+	max := scores[0]
+	for _, score := range scores[1:] {
+		max = math.Max(max, score)
+	}
+	return max
+}
+
+func sorted(scores []float64) []float64 {
+	s := make([]float64, len(scores))
+	copy(s, scores)
+	sort.Float64s(s)
+	return s
+}
+
+// quantile interpolates linearly between the closest ranks, level is clamped to [0, 1].
+func quantile(scores []float64, level float64) float64 {
+	s := sorted(scores)
+	level = math.Max(0, math.Min(1, level))
+	pos := level * float64(len(s)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return s[lower] + (s[upper]-s[lower])*(pos-float64(lower))
+}
+
+// trimmedMean drops floor(trim * n) scores from each end, keeping at least one score.
+func trimmedMean(scores []float64, trim float64) float64 {
+	s := sorted(scores)
+	drop := int(math.Floor(math.Min(trim, 0.5) * float64(len(s))))
+	if 2*drop >= len(s) {
+		drop = (len(s) - 1) / 2
+	}
+	return mean(s[drop : len(s)-drop])
+}
+
+// cvar is the mean of the ceil(level * n) lowest scores.
+func cvar(scores []float64, level float64) float64 {
+	s := sorted(scores)
+	worst := int(math.Ceil(math.Min(level, 1) * float64(len(s))))
+	if worst < 1 {
+		worst = 1
+	}
+	return mean(s[:worst])
+}
@@ -0,0 +1,82 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testScores = []float64{4, -2, 10, 1, 7, 3, 0, 5, 6, 2}
+
+func TestAggregateStrategies(t *testing.T) {
+	aggregate := func(config SimulationConfig) float64 {
+		return config.aggregate(testScores)
+	}
+
+	assert.Equal(t, 3.6, aggregate(SimulationConfig{}))
+	assert.Equal(t, 3.6, aggregate(SimulationConfig{Strategy: Avg}))
+	assert.Equal(t, -2.0, aggregate(SimulationConfig{Strategy: Min}))
+	assert.Equal(t, 10.0, aggregate(SimulationConfig{Strategy: Max}))
+	// sorted: -2 0 1 2 3 4 5 6 7 10
+	assert.Equal(t, 3.5, aggregate(SimulationConfig{Strategy: Median}))
+	// drops -2 and 10
+	assert.Equal(t, 3.5, aggregate(SimulationConfig{Strategy: TrimmedMean}))
+	// drops -2 0 and 7 10
+	assert.Equal(t, 3.5, aggregate(SimulationConfig{Strategy: TrimmedMean, Trim: 0.2}))
+	// position 0.9 between -2 and 0
+	assert.InDelta(t, -0.2, aggregate(SimulationConfig{Strategy: Quantile}), 1e-9)
+	assert.InDelta(t, 6.2, aggregate(SimulationConfig{Strategy: Quantile, Level: 0.8}), 1e-9)
+	assert.Equal(t, -2.0, aggregate(SimulationConfig{Strategy: CVaR}))
+	// mean of -2 0 1
+	assert.InDelta(t, -1.0/3, aggregate(SimulationConfig{Strategy: CVaR, Level: 0.25}), 1e-9)
+	assert.Equal(t, 10.0, aggregate(SimulationConfig{Strategy: Custom, Aggregate: func(scores []float64) float64 {
+		return float64(len(scores))
+	}}))
+	// custom without aggregator falls back to the mean
+	assert.Equal(t, 3.6, aggregate(SimulationConfig{Strategy: Custom}))
+	// the scores aren't reordered
+	assert.Equal(t, 4.0, testScores[0])
+}
+
+func TestQuantileBounds(t *testing.T) {
+	assert.Equal(t, -2.0, quantile(testScores, 0))
+	assert.Equal(t, 10.0, quantile(testScores, 1))
+	assert.Equal(t, 10.0, quantile(testScores, 2))
+	assert.Equal(t, 7.0, quantile([]float64{7}, 0.3))
+}
+
+func TestTrimmedMeanKeepsOneScore(t *testing.T) {
+	assert.Equal(t, 2.0, trimmedMean([]float64{1, 2, 9}, 0.5))
+	assert.Equal(t, 2.5, trimmedMean([]float64{1, 2, 3, 9}, 0.5))
+}
+
+func TestSimulationRatio(t *testing.T) {
+	assert.Equal(t, 1, SimulationConfig{}.runs())
+	assert.Equal(t, 1, SimulationConfig{Ratio: -3}.runs())
+	assert.Equal(t, 10, SimulationConfig{Ratio: 10}.runs())
+
+	runs := 0
+	state := countingSimulation{runs: &runs}
+	score, _ := recordedSimulation(state, SimulationConfig{Ratio: 4, Strategy: Custom, Aggregate: func(scores []float64) float64 {
+		return float64(len(scores))
+	}}, false)
+	assert.Equal(t, 4.0, score)
+	assert.Equal(t, 4, runs)
+
+	// the mean of rollouts keeps the scale of a single simulation
+	score, _ = recordedSimulation(state, SimulationConfig{Ratio: 5}, false)
+	assert.Equal(t, 1.0, score)
+}
+
+type countingSimulation struct {
+	sumState
+	runs *int
+}
+
+func (c countingSimulation) Simulate() float64 {
+	*c.runs++
+	return 1
+}
+
+func (c countingSimulation) Copy() State {
+	return c
+}
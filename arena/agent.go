@@ -0,0 +1,116 @@
+package arena
+
+import (
+	"fmt"
+
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
+)
+
+// Agent chooses the moves of one seat. Agents must be safe for concurrent use
+// when the arena runs games in parallel.
+type Agent interface {
+	Name() string
+	// Play returns the game after the agent move, seat is the index of the player the agent plays.
+	Play(g Game, seat int) (Game, error)
+}
+
+// PlayerAgent is the Agent playing the moves of a play.Player.
+type PlayerAgent struct {
+	play.Player
+}
+
+func (a PlayerAgent) Play(g Game, seat int) (Game, error) {
+	move, err := a.Choose(g, seat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.Name(), err)
+	}
+	next := g.Copy().Expand(move)
+	if next == nil {
+		return nil, fmt.Errorf("%s: invalid move %v", a.Name(), move)
+	}
+	return next.(Game), nil
+}
+
+func (a PlayerAgent) player() play.Player {
+	return a.Player
+}
+
+// MCTSAgent plays the most visited move of a new MonteCarloTree per turn, see play.MCTSPlayer.
+type MCTSAgent struct {
+	AgentName string
+	Config    mcts.MonteCarloTreeConfig
+}
+
+func (a MCTSAgent) Name() string {
+	return a.AgentName
+}
+
+func (a MCTSAgent) Play(g Game, seat int) (Game, error) {
+	return PlayerAgent{a.player()}.Play(g, seat)
+}
+
+func (a MCTSAgent) player() play.Player {
+	return play.MCTSPlayer{PlayerName: a.AgentName, Config: a.Config}
+}
+
+// RandomAgent plays uniformly random moves.
+type RandomAgent struct{}
+
+func (RandomAgent) Name() string {
+	return "random"
+}
+
+func (a RandomAgent) Play(g Game, seat int) (Game, error) {
+	return PlayerAgent{a.player()}.Play(g, seat)
+}
+
+func (RandomAgent) player() play.Player {
+	return play.RandomPlayer{}
+}
+
+// ScriptedAgent plays the move returned by Choose, which must be one of the game Iterations.
+type ScriptedAgent struct {
+	AgentName string
+	Choose    func(g Game, seat int) any
+}
+
+func (a ScriptedAgent) Name() string {
+	return a.AgentName
+}
+
+func (a ScriptedAgent) Play(g Game, seat int) (Game, error) {
+	return PlayerAgent{a.player()}.Play(g, seat)
+}
+
+func (a ScriptedAgent) player() play.Player {
+	return play.ScriptedPlayer{PlayerName: a.AgentName, Script: func(g play.Game, seat int) any {
+		return a.Choose(g.(Game), seat)
+	}}
+}
+
+// playerOf returns the play.Player choosing the moves of a. The agents of this package are built
+// on one, the moves of other agents are found back from the game they play.
+func playerOf(a Agent) play.Player {
+	if p, ok := a.(interface{ player() play.Player }); ok {
+		return p.player()
+	}
+	return agentPlayer{a}
+}
+
+type agentPlayer struct {
+	Agent
+}
+
+func (p agentPlayer) Choose(g play.Game, seat int) (any, error) {
+	next, err := p.Play(g.(Game), seat)
+	if err != nil {
+		return nil, err
+	}
+	for _, iter := range g.Iterations() {
+		if after := g.Copy().Expand(iter); after != nil && after.ID() == next.ID() {
+			return iter, nil
+		}
+	}
+	return nil, fmt.Errorf("no move leads to %s", next.ID())
+}
@@ -0,0 +1,137 @@
+// Package arena plays many games between agents to compare their strength.
+package arena
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
+)
+
+// Draw is the winner of a game nobody won.
+const Draw = play.Draw
+
+// Game is a two player, zero-sum, turn based game.
+// Simulate must score the game for seat 0: positive when seat 0 wins and negative when seat 1 wins.
+type Game interface {
+	mcts.State
+	// Turn returns the seat of the player to move.
+	Turn() int
+	// Winner returns the winner seat or Draw, and whether the game is over.
+	Winner() (int, bool)
+}
+
+// Config defines a match between two agents.
+type Config struct {
+	NewGame func() Game
+	Games   int
+	// Parallel is how many games are played at the same time, GOMAXPROCS by default.
+	Parallel int
+	// MaxTurns ends a game as a draw after MaxTurns moves, zero means no limit.
+	MaxTurns int
+}
+
+// Result is the outcome of one game, from the point of view of agent A.
+type Result string
+
+const (
+	Win  Result = "win"
+	Loss Result = "loss"
+	Tie  Result = "draw"
+)
+
+// Match plays cfg.Games games between a and b, swapping seats every game,
+// and reports the results from a point of view.
+func Match(cfg Config, a, b Agent) (Report, error) {
+	if cfg.NewGame == nil {
+		return Report{}, fmt.Errorf("arena: NewGame is required")
+	}
+	parallel := cfg.Parallel
+	if parallel <= 0 {
+		parallel = runtime.GOMAXPROCS(0)
+	}
+
+	games := make(chan int)
+	results := make(chan gameResult)
+	wg := sync.WaitGroup{}
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range games {
+				result, err := playMatchGame(cfg, a, b, idx%2 == 1)
+				results <- gameResult{result: result, err: err}
+			}
+		}()
+	}
+	go func() {
+		for idx := 0; idx < cfg.Games; idx++ {
+			games <- idx
+		}
+		close(games)
+		wg.Wait()
+		close(results)
+	}()
+
+	report := Report{A: a.Name(), B: b.Name()}
+	var firstErr error
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		report.add(res.result)
+	}
+	if firstErr != nil {
+		return Report{}, firstErr
+	}
+	report.compute()
+	return report, nil
+}
+
+// RoundRobin plays a match between every pair of agents.
+func RoundRobin(cfg Config, agents ...Agent) ([]Report, error) {
+	reports := make([]Report, 0)
+	for i := 0; i < len(agents); i++ {
+		for j := i + 1; j < len(agents); j++ {
+			report, err := Match(cfg, agents[i], agents[j])
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+type gameResult struct {
+	result Result
+	err    error
+}
+
+// playMatchGame plays one game with a in seat 0, or in seat 1 when swapped.
+func playMatchGame(cfg Config, a, b Agent, swapped bool) (Result, error) {
+	players := []play.Player{playerOf(a), playerOf(b)}
+	aSeat := 0
+	if swapped {
+		players[0], players[1] = players[1], players[0]
+		aSeat = 1
+	}
+
+	res, err := play.Run(cfg.NewGame(), players, play.Options{MaxTurns: cfg.MaxTurns})
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !res.Over || res.Winner == Draw:
+		return Tie, nil
+	case res.Winner == aSeat:
+		return Win, nil
+	default:
+		return Loss, nil
+	}
+}
@@ -0,0 +1,136 @@
+package arena
+
+import (
+	"fmt"
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// perfectNim always leaves a multiple of 4 stones when it can.
+var perfectNim = ScriptedAgent{
+	AgentName: "perfect",
+	Choose: func(g Game, _ int) any {
+		take := g.(Nim).Stones % 4
+		if take == 0 {
+			take = 1
+		}
+		return take
+	},
+}
+
+func TestMatchMCTSAgainstRandom(t *testing.T) {
+	// one game at a time over the seeded global source, so the games and the score are repeatable
+	rand.Seed(1)
+	agent := MCTSAgent{AgentName: "mcts", Config: mcts.MonteCarloTreeConfig{MaxIterations: 300}}
+	report, err := Match(Config{NewGame: func() Game { return NewNim(10) }, Games: 40, Parallel: 1}, agent, RandomAgent{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "mcts", report.A)
+	assert.Equal(t, "random", report.B)
+	assert.Equal(t, 40, report.Games)
+	assert.Equal(t, 40, report.Wins+report.Draws+report.Losses)
+	assert.Greater(t, report.Score, 0.7)
+	assert.Less(t, report.ScoreLow, report.Score)
+	assert.Greater(t, report.ScoreHigh, report.Score)
+	assert.Greater(t, report.Elo, 0.0)
+}
+
+func TestMatchScripted(t *testing.T) {
+	// with 8 stones the second player always wins, seats are swapped every game
+	report, err := Match(Config{NewGame: func() Game { return NewNim(8) }, Games: 10}, perfectNim, perfectNim)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Wins)
+	assert.Equal(t, 5, report.Losses)
+	assert.Equal(t, 0.5, report.Score)
+	assert.InDelta(t, 0, report.Elo, 1e-9)
+}
+
+func TestMatchMaxTurns(t *testing.T) {
+	report, err := Match(Config{NewGame: func() Game { return NewNim(20) }, Games: 2, MaxTurns: 3}, RandomAgent{}, RandomAgent{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Draws)
+}
+
+func TestMatchError(t *testing.T) {
+	broken := ScriptedAgent{AgentName: "broken", Choose: func(Game, int) any { return nil }}
+	_, err := Match(Config{NewGame: func() Game { return NewNim(5) }, Games: 4}, broken, RandomAgent{})
+	assert.Error(t, err)
+
+	_, err = Match(Config{Games: 1}, RandomAgent{}, RandomAgent{})
+	assert.Error(t, err)
+}
+
+// takeOne is an Agent outside of the ones built on a play.Player.
+type takeOne struct{}
+
+func (takeOne) Name() string {
+	return "one"
+}
+
+func (takeOne) Play(g Game, _ int) (Game, error) {
+	return g.Copy().Expand(1).(Game), nil
+}
+
+func TestMatchCustomAgents(t *testing.T) {
+	// takeOne gives the perfect player a winning pile from either seat
+	perfect := PlayerAgent{play.ScriptedPlayer{PlayerName: "perfect", Script: func(g play.Game, seat int) any {
+		return perfectNim.Choose(g.(Game), seat)
+	}}}
+	report, err := Match(Config{NewGame: func() Game { return NewNim(8) }, Games: 2}, perfect, takeOne{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Wins)
+
+	next, err := perfect.Play(NewNim(6), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, next.(Nim).Stones)
+}
+
+func TestRoundRobin(t *testing.T) {
+	agents := []Agent{perfectNim, RandomAgent{}, ScriptedAgent{AgentName: "one", Choose: func(Game, int) any { return 1 }}}
+	reports, err := RoundRobin(Config{NewGame: func() Game { return NewNim(7) }, Games: 6}, agents...)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 3)
+	assert.Equal(t, fmt.Sprintf("%s-%s", "perfect", "random"), reports[0].A+"-"+reports[0].B)
+	assert.Equal(t, "random", reports[2].A)
+}
+
+func TestWilson(t *testing.T) {
+	low, high := wilson(0.5, 100)
+	assert.InDelta(t, 0.4038, low, 1e-4)
+	assert.InDelta(t, 0.5962, high, 1e-4)
+}
+
+func TestElo(t *testing.T) {
+	assert.InDelta(t, 0, elo(0.5), 1e-9)
+	assert.InDelta(t, 190.85, elo(0.75), 1e-2)
+	assert.InDelta(t, -190.85, elo(0.25), 1e-2)
+	assert.Equal(t, 1000.0, elo(1))
+	assert.Equal(t, -1000.0, elo(0))
+}
+
+// BenchmarkParallelStrength plays parallel searches against the sequential one with the same
+// iterations, a score close to 0.5 means the virtual loss keeps the playing strength.
+func BenchmarkParallelStrength(b *testing.B) {
+	sequential := MCTSAgent{AgentName: "sequential", Config: mcts.MonteCarloTreeConfig{MaxIterations: 300}}
+	for _, lock := range []mcts.LockStrategy{mcts.GlobalLock, mcts.NodeLock, mcts.LockFree} {
+		b.Run(string(lock), func(b *testing.B) {
+			parallel := MCTSAgent{AgentName: string(lock), Config: mcts.MonteCarloTreeConfig{
+				MaxIterations: 300,
+				Parallel:      mcts.ParallelConfig{Workers: 4, Lock: lock},
+			}}
+			games, score := 0, 0.0
+			for i := 0; i < b.N; i++ {
+				report, err := Match(Config{NewGame: func() Game { return NewNim(15) }, Games: 20}, parallel, sequential)
+				if err != nil {
+					b.Fatal(err)
+				}
+				games += report.Games
+				score += report.Score * float64(report.Games)
+			}
+			b.ReportMetric(score/float64(games), "score")
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package arena
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/danielsussa/mcts"
+)
+
+// Nim is a small Game where players take 1 to 3 stones in turns, whoever takes the last stone wins.
+// It's used by the arena command and as an example of Game.
+type Nim struct {
+	Stones int
+	turn   int
+	winner int
+}
+
+// NewNim creates a Nim game with stones on the pile.
+func NewNim(stones int) Game {
+	return Nim{Stones: stones, winner: Draw}
+}
+
+func (n Nim) Turn() int {
+	return n.turn
+}
+
+func (n Nim) Winner() (int, bool) {
+	return n.winner, n.Stones == 0
+}
+
+func (n Nim) Iterations() []any {
+	iters := make([]any, 0, 3)
+	for take := 1; take <= 3 && take <= n.Stones; take++ {
+		iters = append(iters, take)
+	}
+	return iters
+}
+
+func (n Nim) Expand(iter any) mcts.State {
+	n.Stones -= iter.(int)
+	if n.Stones == 0 {
+		n.winner = n.turn
+	}
+	n.turn = 1 - n.turn
+	return n
+}
+
+func (n Nim) Simulate() float64 {
+	for n.Stones > 0 {
+		iters := n.Iterations()
+		n = n.Expand(iters[rand.Intn(len(iters))]).(Nim)
+	}
+	if n.winner == 0 {
+		return 1
+	}
+	return -1
+}
+
+func (n Nim) Copy() mcts.State {
+	return n
+}
+
+func (n Nim) ID() string {
+	return fmt.Sprintf("%d-%d", n.Stones, n.turn)
+}
@@ -0,0 +1,78 @@
+package arena
+
+import (
+	"fmt"
+	"math"
+)
+
+// z95 is the normal quantile of a 95% confidence interval.
+const z95 = 1.959964
+
+// Report holds the results of a match from the point of view of agent A.
+type Report struct {
+	A, B   string
+	Games  int
+	Wins   int
+	Draws  int
+	Losses int
+
+	// Score is (wins + draws/2) / games, with its 95% Wilson confidence interval.
+	Score     float64
+	ScoreLow  float64
+	ScoreHigh float64
+
+	// Elo is the estimated rating difference of A over B, with its 95% confidence interval.
+	Elo     float64
+	EloLow  float64
+	EloHigh float64
+}
+
+func (r *Report) add(result Result) {
+	r.Games++
+	switch result {
+	case Win:
+		r.Wins++
+	case Loss:
+		r.Losses++
+	default:
+		r.Draws++
+	}
+}
+
+func (r *Report) compute() {
+	if r.Games == 0 {
+		return
+	}
+	n := float64(r.Games)
+	r.Score = (float64(r.Wins) + float64(r.Draws)/2) / n
+	r.ScoreLow, r.ScoreHigh = wilson(r.Score, n)
+	r.Elo = elo(r.Score)
+	r.EloLow = elo(r.ScoreLow)
+	r.EloHigh = elo(r.ScoreHigh)
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%s vs %s: +%d =%d -%d (%d games) score %.3f [%.3f, %.3f] elo %+.0f [%+.0f, %+.0f]",
+		r.A, r.B, r.Wins, r.Draws, r.Losses, r.Games,
+		r.Score, r.ScoreLow, r.ScoreHigh, r.Elo, r.EloLow, r.EloHigh)
+}
+
+// wilson returns the 95% Wilson score interval of proportion p over n games.
+func wilson(p, n float64) (float64, float64) {
+	z2 := z95 * z95
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z95 / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// elo converts an expected score into a rating difference, infinite scores are capped.
+func elo(score float64) float64 {
+	const maxElo = 1000
+	if score <= 0 {
+		return -maxElo
+	}
+	if score >= 1 {
+		return maxElo
+	}
+	return math.Max(-maxElo, math.Min(maxElo, -400*math.Log10(1/score-1)))
+}
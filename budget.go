@@ -0,0 +1,145 @@
+package mcts
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// NodeLimitPolicy defines what the tree does once MaxNodes or MaxMemory is reached.
+type NodeLimitPolicy string
+
+const (
+	// StopExpanding keeps searching over the existing nodes without creating new ones.
+	StopExpanding NodeLimitPolicy = "stop"
+	// PruneColdNodes collapses the least visited subtrees to make room for new nodes.
+	PruneColdNodes NodeLimitPolicy = "prune"
+)
+
+// pruneRatio is the fraction of the budget the tree is pruned down to,
+// so pruning doesn't run again on the very next iteration.
+const pruneRatio = 0.9
+
+// nodeOverhead is the approximate memory used by one node, without its state.
+var nodeOverhead = uint64(unsafe.Sizeof(Node{})) + uint64(unsafe.Sizeof(&Node{}))
+
+// SizedState can be implemented by a State to report its approximate size in bytes,
+// which is then accounted in MaxMemory.
+type SizedState interface {
+	Size() uint64
+}
+
+type nodeBudget struct {
+	maxNodes  uint
+	maxMemory uint64
+	policy    NodeLimitPolicy
+
+	nodes  uint
+	memory uint64
+}
+
+func nodeMemory(n *Node) uint64 {
+	size := nodeOverhead
+	if sized, ok := n.state.(SizedState); ok {
+		size += sized.Size()
+	}
+	return size
+}
+
+func (b *nodeBudget) add(n *Node) {
+	b.nodes++
+	b.memory += nodeMemory(n)
+}
+
+func (b *nodeBudget) remove(n *Node) {
+	b.nodes--
+	b.memory -= nodeMemory(n)
+}
+
+func (b *nodeBudget) reset() {
+	b.nodes = 0
+	b.memory = 0
+}
+
+func (b *nodeBudget) limitReached() bool {
+	if b.maxNodes > 0 && b.nodes >= b.maxNodes {
+		return true
+	}
+	if b.maxMemory > 0 && b.memory >= b.maxMemory {
+		return true
+	}
+	return false
+}
+
+func (b *nodeBudget) pruneTargetReached() bool {
+	if b.maxNodes > 0 && float64(b.nodes) > float64(b.maxNodes)*pruneRatio {
+		return false
+	}
+	if b.maxMemory > 0 && float64(b.memory) > float64(b.maxMemory)*pruneRatio {
+		return false
+	}
+	return true
+}
+
+// prune collapses the least visited subtrees below root until the tree is back under
+// the prune target. Collapsed nodes keep their statistics and can be expanded again.
+func (b *nodeBudget) prune(root *Node) {
+	candidates := make([]*Node, 0)
+	var collect func(n *Node)
+	collect = func(n *Node) {
+		for _, child := range n.child {
+			if len(child.child) == 0 {
+				continue
+			}
+			candidates = append(candidates, child)
+			collect(child)
+		}
+	}
+	collect(root)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].nVisited < candidates[j].nVisited
+	})
+
+	for _, candidate := range candidates {
+		if b.pruneTargetReached() {
+			return
+		}
+		if !candidate.attachedTo(root) {
+			continue
+		}
+		b.collapse(candidate)
+	}
+}
+
+func (b *nodeBudget) collapse(n *Node) {
+	for _, child := range n.child {
+		b.collapse(child)
+		b.remove(child)
+		child.parent = nil
+	}
+	n.child = nil
+	n.iterations = nil
+	n.currIterationIdx = 0
+}
+
+func (n *Node) attachedTo(root *Node) bool {
+	for curr := n; curr != nil; curr = curr.parent {
+		if curr == root {
+			return true
+		}
+	}
+	return false
+}
+
+// recount resets the budget to the nodes under root.
+func (b *nodeBudget) recount(root *Node) {
+	b.reset()
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		b.add(n)
+		for _, child := range n.child {
+			walk(child)
+		}
+	}
+	walk(root)
+}
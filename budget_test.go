@@ -0,0 +1,63 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func countNodes(n *Node) uint {
+	total := uint(1)
+	for _, child := range n.child {
+		total += countNodes(child)
+	}
+	return total
+}
+
+func TestMaxNodesStopExpanding(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 500, MaxNodes: 20})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(500), res.Iterations)
+	assert.Equal(t, uint(20), countNodes(tree.node))
+	assert.Equal(t, uint(20), tree.budget.nodes)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+}
+
+func TestMaxNodesPruneColdNodes(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations:   500,
+		MaxNodes:        20,
+		NodeLimitPolicy: PruneColdNodes,
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, countNodes(tree.node), uint(20))
+	assert.Equal(t, countNodes(tree.node), tree.budget.nodes)
+	assert.Equal(t, uint(500), tree.node.nVisited)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+}
+
+func TestMaxMemory(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 500, MaxMemory: nodeOverhead * 10})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), countNodes(tree.node))
+	assert.Equal(t, nodeOverhead*10, tree.budget.memory)
+}
+
+func TestPruneKeepsHotSubtree(t *testing.T) {
+	root := &Node{nVisited: 10}
+	hot := &Node{nVisited: 8, parent: root}
+	cold := &Node{nVisited: 2, parent: root}
+	root.child = []*Node{hot, cold}
+	hot.child = []*Node{{nVisited: 7, parent: hot}}
+	cold.child = []*Node{{nVisited: 1, parent: cold}}
+
+	budget := nodeBudget{maxNodes: 5, nodes: 5}
+	budget.prune(root)
+
+	assert.Equal(t, uint(4), budget.nodes)
+	assert.Len(t, hot.child, 1)
+	assert.Nil(t, cold.child)
+	assert.Equal(t, uint(2), cold.nVisited)
+}
@@ -0,0 +1,85 @@
+// Command mcts-arena plays Nim matches between agents and reports their relative strength.
+//
+//	mcts-arena -games 200 mcts:1000 mcts:200:5:max random
+//
+// Agents are random or mcts:<iterations>[:<ratio>[:<strategy>]]. With more than two agents,
+// every pair plays a match.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/arena"
+)
+
+func main() {
+	games := flag.Int("games", 100, "games per match")
+	parallel := flag.Int("parallel", 0, "games played at the same time, GOMAXPROCS by default")
+	stones := flag.Int("stones", 21, "stones of the Nim pile")
+	maxTurns := flag.Int("max-turns", 0, "turns before a game is a draw, zero means no limit")
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "at least two agents are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	agents := make([]arena.Agent, 0, flag.NArg())
+	for _, spec := range flag.Args() {
+		agent, err := parseAgent(spec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		agents = append(agents, agent)
+	}
+
+	reports, err := arena.RoundRobin(arena.Config{
+		NewGame:  func() arena.Game { return arena.NewNim(*stones) },
+		Games:    *games,
+		Parallel: *parallel,
+		MaxTurns: *maxTurns,
+	}, agents...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, report := range reports {
+		fmt.Println(report)
+	}
+}
+
+func parseAgent(spec string) (arena.Agent, error) {
+	parts := strings.Split(spec, ":")
+	switch parts[0] {
+	case "random":
+		return arena.RandomAgent{}, nil
+	case "mcts":
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("invalid agent %q, use mcts:<iterations>[:<ratio>[:<strategy>]]", spec)
+		}
+		iterations, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid iterations of agent %q: %w", spec, err)
+		}
+		config := mcts.MonteCarloTreeConfig{MaxIterations: uint(iterations)}
+		if len(parts) > 2 {
+			ratio, err := strconv.Atoi(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ratio of agent %q: %w", spec, err)
+			}
+			config.SimulationConfig.Ratio = ratio
+		}
+		if len(parts) > 3 {
+			config.SimulationConfig.Strategy = mcts.ScoreStrategy(parts[3])
+		}
+		return arena.MCTSAgent{AgentName: spec, Config: config}, nil
+	}
+	return nil, fmt.Errorf("unknown agent %q", spec)
+}
@@ -0,0 +1,44 @@
+// Command mcts-explorer navigates a search tree saved with MonteCarloTree.Save.
+//
+//	mcts-explorer -tree search.bin
+//
+// Trees saved with a codec can't be decoded here, use explorer.Run from the program
+// that owns the states instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/explorer"
+)
+
+func main() {
+	path := flag.String("tree", "", "path of the tree saved with MonteCarloTree.Save")
+	flag.Parse()
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{})
+	if err := tree.Load(f, nil, nil); err != nil {
+		return err
+	}
+	return explorer.Run(tree.Root(), os.Stdin, os.Stdout)
+}
@@ -0,0 +1,55 @@
+package mcts
+
+// CopyPolicy tells the tree which State methods are safe to call without copying the state first.
+type CopyPolicy struct {
+	// IterationsReadOnly means Iterations doesn't mutate the state.
+	IterationsReadOnly bool
+	// SimulateReadOnly means Simulate doesn't mutate the state.
+	SimulateReadOnly bool
+	// ExpandReturnsFresh means Expand doesn't mutate the state and returns a new one
+	// that shares no mutable data with it.
+	ExpandReturnsFresh bool
+}
+
+// CopyAwareState can be implemented by a State to skip redundant Copy calls.
+// States not implementing it are always copied before Iterations, Expand and Simulate.
+type CopyAwareState interface {
+	CopyPolicy() CopyPolicy
+}
+
+func copyPolicyOf(s State) CopyPolicy {
+	if aware, ok := s.(CopyAwareState); ok {
+		return aware.CopyPolicy()
+	}
+	return CopyPolicy{}
+}
+
+func stateFor(s State, readOnly bool) State {
+	if readOnly {
+		return s
+	}
+	return s.Copy()
+}
+
+func (n *Node) iterationsOf() []any {
+	return stateFor(n.state, copyPolicyOf(n.state).IterationsReadOnly).Iterations()
+}
+
+func (n *Node) expandState(iter any) State {
+	state, _ := n.expandReward(iter)
+	return state
+}
+
+// expandReward expands iter with the reward of the transition, zero for states not implementing RewardState.
+func (n *Node) expandReward(iter any) (State, float64) {
+	return transition(stateFor(n.state, copyPolicyOf(n.state).ExpandReturnsFresh), iter)
+}
+
+// simulate runs State.Simulate of state, or RecordingState.SimulateActions when record is set.
+func simulate(state State, record bool) (float64, []any) {
+	state = stateFor(state, copyPolicyOf(state).SimulateReadOnly)
+	if recording, ok := state.(RecordingState); ok && record {
+		return recording.SimulateActions()
+	}
+	return state.Simulate(), nil
+}
@@ -0,0 +1,42 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type copyCountState struct {
+	sumState
+	copies *int
+	policy CopyPolicy
+}
+
+func (s copyCountState) Copy() State {
+	*s.copies++
+	return s
+}
+
+func (s copyCountState) Expand(iter any) State {
+	return copyCountState{sumState: s.sumState.Expand(iter).(sumState), copies: s.copies, policy: s.policy}
+}
+
+func (s copyCountState) CopyPolicy() CopyPolicy {
+	return s.policy
+}
+
+func TestCopyPolicy(t *testing.T) {
+	run := func(policy CopyPolicy) int {
+		copies := 0
+		tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+		_, err := tree.Start(copyCountState{sumState: newSumState(), copies: &copies, policy: policy})
+		assert.NoError(t, err)
+		return copies
+	}
+
+	// one copy for the root, then Iterations, Expand and Simulate are copied per node
+	all := run(CopyPolicy{})
+	none := run(CopyPolicy{IterationsReadOnly: true, SimulateReadOnly: true, ExpandReturnsFresh: true})
+
+	assert.Equal(t, 1, none)
+	assert.Greater(t, all, 100)
+}
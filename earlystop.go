@@ -0,0 +1,74 @@
+package mcts
+
+// StopReason tells which rule ended the search.
+type StopReason string
+
+const (
+	StopMaxIterations StopReason = "max_iterations"
+	StopTimeout       StopReason = "timeout"
+	StopProgress      StopReason = "progress"
+	// StopUnreachable means the second best move could no longer overtake the best one.
+	StopUnreachable StopReason = "unreachable"
+	// StopStable means the best move didn't change for EarlyStopConfig.StableIterations.
+	StopStable StopReason = "stable"
+)
+
+// EarlyStopConfig defines optional rules to end the search before MaxIterations.
+type EarlyStopConfig struct {
+	// Unreachable stops when the remaining iterations can't make another root child
+	// more visited than the best one. It requires MaxIterations.
+	Unreachable bool
+	// StableIterations stops once the most visited root child stays the same for this many iterations.
+	StableIterations uint
+}
+
+type earlyStopTracker struct {
+	config      EarlyStopConfig
+	best        *Node
+	stableSince uint
+}
+
+func (e *earlyStopTracker) check(mct *MonteCarloTree, iterations uint) (StopReason, bool) {
+	if !e.config.Unreachable && e.config.StableIterations == 0 {
+		return "", false
+	}
+	best, second := mostVisitedChildren(mct.node)
+	if best == nil {
+		return "", false
+	}
+
+	if e.config.Unreachable && mct.maxInteractions > 0 {
+		remaining := mct.maxInteractions - iterations
+		secondVisits := uint(0)
+		if second != nil {
+			secondVisits = second.nVisited
+		}
+		// the second child can at most tie when the gap equals the remaining iterations
+		if best.nVisited-secondVisits >= remaining {
+			return StopUnreachable, true
+		}
+	}
+
+	if e.config.StableIterations > 0 {
+		if best != e.best {
+			e.best = best
+			e.stableSince = iterations
+		}
+		if iterations-e.stableSince >= e.config.StableIterations {
+			return StopStable, true
+		}
+	}
+	return "", false
+}
+
+func mostVisitedChildren(n *Node) (*Node, *Node) {
+	var best, second *Node
+	for _, child := range n.child {
+		if best == nil || child.nVisited > best.nVisited {
+			best, second = child, best
+		} else if second == nil || child.nVisited > second.nVisited {
+			second = child
+		}
+	}
+	return best, second
+}
@@ -0,0 +1,65 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEarlyStopUnreachable(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: 2000,
+		EarlyStop:     EarlyStopConfig{Unreachable: true},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, StopUnreachable, res.StopReason)
+	assert.Less(t, res.Iterations, uint(2000))
+
+	best, second := mostVisitedChildren(tree.node)
+	assert.GreaterOrEqual(t, best.nVisited-second.nVisited, 2000-res.Iterations)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+}
+
+func TestEarlyStopUnreachableBoundary(t *testing.T) {
+	root := &Node{child: []*Node{{nVisited: 5}, {nVisited: 2}}}
+	tree := MonteCarloTree{node: root, maxInteractions: 10}
+	tracker := earlyStopTracker{config: EarlyStopConfig{Unreachable: true}}
+
+	// a gap of 3 with 4 iterations left can still be overtaken
+	_, stop := tracker.check(&tree, 6)
+	assert.False(t, stop)
+	// with 3 left the second child can only tie
+	reason, stop := tracker.check(&tree, 7)
+	assert.True(t, stop)
+	assert.Equal(t, StopUnreachable, reason)
+}
+
+func TestEarlyStopStable(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: 2000,
+		EarlyStop:     EarlyStopConfig{StableIterations: 100},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, StopStable, res.StopReason)
+	assert.Less(t, res.Iterations, uint(2000))
+}
+
+func TestStopMaxIterations(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 10})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, StopMaxIterations, res.StopReason)
+}
+
+func TestMostVisitedChildren(t *testing.T) {
+	parent := &Node{}
+	c1 := &Node{nVisited: 3}
+	c2 := &Node{nVisited: 7}
+	c3 := &Node{nVisited: 5}
+	parent.child = []*Node{c1, c2, c3}
+
+	best, second := mostVisitedChildren(parent)
+	assert.Equal(t, c2, best)
+	assert.Equal(t, c3, second)
+}
@@ -3,6 +3,7 @@ package g2048
 import (
 	"fmt"
 	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
 	"math/rand"
 )
 
@@ -226,6 +227,12 @@ func (g g2048) ID() string {
 	return ""
 }
 
+// Chance adds the new tile that follows every move.
+func (g g2048) Chance() play.Game {
+	addNumberOnBoard(g.board)
+	return g
+}
+
 func (g g2048) Expand(i interface{}) mcts.State {
 	score := 0
 	if i.(string) == "D" {
@@ -2,6 +2,7 @@ package g2048
 
 import (
 	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
 	"testing"
@@ -26,35 +27,22 @@ func TestG2048(t *testing.T) {
 	game2048 := startNewGame()
 	addNumberOnBoard(game2048.board)
 
-	totalIterations := 0
-
 	rand.Seed(1)
 
-	for {
-		print2048(game2048.board, game2048.score)
-
-		tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 64, SimulationConfig: mcts.SimulationConfig{
-			Ratio:    10,
-			Strategy: mcts.Avg,
-		}})
-		nodes, err := tree.Start(game2048)
-		assert.NoError(t, err)
-
-		if len(nodes.NodeScore) == 0 {
-			break
-		}
-
-		//if totalIterations % 200 == 0 {
-		//	print2048(game2048.board, game2048.score)
-		//}
-
-		game2048 = nodes.NodeScore[0].State.(g2048)
-		print2048(game2048.board, game2048.score)
-		// add new move
-		addNumberOnBoard(game2048.board)
-		totalIterations++
-	}
-	print2048(game2048.board, game2048.score)
-	game2048.stats.print()
-
+	player := play.MCTSPlayer{PlayerName: "mcts", Config: mcts.MonteCarloTreeConfig{MaxIterations: 64, SimulationConfig: mcts.SimulationConfig{
+		Ratio:    10,
+		Strategy: mcts.Avg,
+	}}}
+	res, err := play.Run(game2048, []play.Player{player}, play.Options{Hooks: play.Hooks{
+		AfterTurn: func(m play.Move) {
+			played := m.After.(g2048)
+			print2048(played.board, played.score)
+		},
+	}})
+	assert.NoError(t, err)
+	assert.True(t, res.Over)
+
+	final := res.Final.(g2048)
+	print2048(final.board, final.score)
+	final.stats.print()
 }
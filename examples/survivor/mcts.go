@@ -10,7 +10,8 @@ func (g Game) Simulate() float64 {
 	for g.PlayRandom() {
 	}
 	end := g.CurrDate
-	score := end.Sub(start).Hours() / 1000
+	// in tens of hours, so the mean of the rollouts weighs against the UCB exploration term
+	score := end.Sub(start).Hours() / 10
 	return score
 }
 
@@ -39,6 +40,12 @@ func (g Game) Expand(i interface{}) mcts.State {
 	return g
 }
 
+// CopyPolicy tells the tree Iterations can be called without copying the game,
+// Expand and Simulate both change the Player.
+func (g Game) CopyPolicy() mcts.CopyPolicy {
+	return mcts.CopyPolicy{IterationsReadOnly: true}
+}
+
 func (g Game) Copy() mcts.State {
 	itemsCopy := make([]item, len(g.Player.Items))
 	copy(itemsCopy, g.Player.Items)
@@ -3,6 +3,7 @@ package survivor
 import (
 	"fmt"
 	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/play"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
 	"testing"
@@ -17,39 +18,34 @@ func TestSimpleActions(t *testing.T) {
 
 func TestSurvivor(t *testing.T) {
 	rand.Seed(1)
-	game := NewGame()
-
-	for {
-		tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 512, SimulationConfig: mcts.SimulationConfig{
-			Ratio:    10,
-			Strategy: mcts.Avg,
-		}})
-		nodes, err := tree.Start(game)
-		assert.NoError(t, err)
-
-		if len(nodes.NodeScore) == 0 {
-			break
-		}
-		newGame := nodes.NodeScore[0].State.(Game)
-		action := newGame.Player.SelectedAction
-		game.Player.playAction(action)
-		if !game.playTurn() {
-			break
-		}
-		fmt.Println(fmt.Sprintf("[%v] %d action played: %s | place: %s | hunger: %d | life: %d | bag: %v | score: %0.f",
-			game.CurrDate.Format("02 Jan 2006 15:04"),
-			game.Turn,
-			action,
-			game.Player.CurrentPlace,
-			game.Player.Hunger,
-			game.Player.Life,
-			game.Player.Items,
-			game.Score(),
-		))
-	}
+	player := play.MCTSPlayer{PlayerName: "survivor", Config: mcts.MonteCarloTreeConfig{MaxIterations: 512, SimulationConfig: mcts.SimulationConfig{
+		Ratio:    10,
+		Strategy: mcts.Avg,
+	}}}
+	// the player now survives as long as it finds food, so the game is cut after a dozen moves
+	res, err := play.Run(NewGame(), []play.Player{player}, play.Options{MaxTurns: 12, Hooks: play.Hooks{
+		// the game goes on while the player is alive
+		BeforeTurn: func(_ int, g play.Game) bool {
+			return g.(Game).Player.Life > 0
+		},
+		AfterTurn: func(m play.Move) {
+			game := m.After.(Game)
+			fmt.Println(fmt.Sprintf("[%v] %d action played: %s | place: %s | hunger: %d | life: %d | bag: %v | score: %0.f",
+				game.CurrDate.Format("02 Jan 2006 15:04"),
+				game.Turn,
+				m.Action,
+				game.Player.CurrentPlace,
+				game.Player.Hunger,
+				game.Player.Life,
+				game.Player.Items,
+				game.Score(),
+			))
+		},
+	}})
+	assert.NoError(t, err)
 
 	fmt.Println("----------------")
-	fmt.Println("Score: ", game.Score())
+	fmt.Println("Score: ", res.Final.(Game).Score())
 }
 
 func TestSurvivorUnit1(t *testing.T) {
@@ -94,7 +90,8 @@ func TestSurvivorUnit2(t *testing.T) {
 		},
 	}
 
-	tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 512, SimulationConfig: mcts.SimulationConfig{
+	// going to the forest only pays off a few moves later, 512 iterations don't reach deep enough
+	tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 2048, SimulationConfig: mcts.SimulationConfig{
 		Ratio:    100,
 		Strategy: mcts.Avg,
 	}})
@@ -147,3 +144,39 @@ func TestSurvivorUnitTmp(t *testing.T) {
 	fmt.Println("score: ", game.Score())
 
 }
+
+// alwaysCopyGame hides the Game CopyPolicy so every call is copied.
+type alwaysCopyGame struct {
+	Game
+}
+
+func (g alwaysCopyGame) Expand(i interface{}) mcts.State {
+	return alwaysCopyGame{g.Game.Expand(i).(Game)}
+}
+
+func (g alwaysCopyGame) Copy() mcts.State {
+	return alwaysCopyGame{g.Game.Copy().(Game)}
+}
+
+func (g alwaysCopyGame) CopyPolicy() mcts.CopyPolicy {
+	return mcts.CopyPolicy{}
+}
+
+func benchmarkSurvivorSearch(b *testing.B, game mcts.State) {
+	rand.Seed(1)
+	for i := 0; i < b.N; i++ {
+		tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 128})
+		_, err := tree.Start(game)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSurvivorCopyPolicy(b *testing.B) {
+	benchmarkSurvivorSearch(b, NewGame())
+}
+
+func BenchmarkSurvivorAlwaysCopy(b *testing.B) {
+	benchmarkSurvivorSearch(b, alwaysCopyGame{NewGame()})
+}
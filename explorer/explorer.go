@@ -0,0 +1,180 @@
+// Package explorer navigates a search tree interactively from a terminal.
+package explorer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danielsussa/mcts"
+)
+
+const help = `commands:
+  ls [visits|value]  list children of the current node
+  cd <n>             descend into child n of the last listing
+  cd ..              go back to the parent node
+  pv [visits|value]  show the principal variation from the current node
+  show               show the current node and its state
+  help               show this help
+  quit               exit`
+
+// Explorer holds the navigation state over a tree.
+type Explorer struct {
+	curr    *mcts.Node
+	order   mcts.PVOrder
+	listing []*mcts.Node
+	out     io.Writer
+}
+
+// New creates an Explorer starting at root, writing to out.
+func New(root *mcts.Node, out io.Writer) *Explorer {
+	return &Explorer{curr: root, order: mcts.MostVisited, out: out}
+}
+
+// Run reads commands from in until quit or EOF.
+func Run(root *mcts.Node, in io.Reader, out io.Writer) error {
+	if root == nil {
+		return fmt.Errorf("tree has no root")
+	}
+	e := New(root, out)
+	scanner := bufio.NewScanner(in)
+	e.prompt()
+	for scanner.Scan() {
+		if !e.Exec(scanner.Text()) {
+			return nil
+		}
+		e.prompt()
+	}
+	return scanner.Err()
+}
+
+// Current returns the node the explorer is at.
+func (e *Explorer) Current() *mcts.Node {
+	return e.curr
+}
+
+// Exec runs one command, returning false when the explorer should quit.
+func (e *Explorer) Exec(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return true
+	}
+	args := fields[1:]
+	switch fields[0] {
+	case "ls":
+		e.list(args)
+	case "cd":
+		e.cd(args)
+	case "pv":
+		e.pv(args)
+	case "show":
+		e.show()
+	case "help":
+		fmt.Fprintln(e.out, help)
+	case "quit", "exit":
+		return false
+	default:
+		fmt.Fprintf(e.out, "unknown command %q, type help\n", fields[0])
+	}
+	return true
+}
+
+func (e *Explorer) prompt() {
+	fmt.Fprintf(e.out, "[%s]> ", e.path())
+}
+
+func (e *Explorer) path() string {
+	steps := make([]string, 0)
+	for n := e.curr; n.Parent() != nil; n = n.Parent() {
+		steps = append([]string{fmt.Sprint(n.Action())}, steps...)
+	}
+	return "/" + strings.Join(steps, "/")
+}
+
+func (e *Explorer) orderFrom(args []string) bool {
+	if len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "visits":
+		e.order = mcts.MostVisited
+	case "value":
+		e.order = mcts.HighestValue
+	default:
+		fmt.Fprintf(e.out, "unknown order %q, use visits or value\n", args[0])
+		return false
+	}
+	return true
+}
+
+func (e *Explorer) list(args []string) {
+	if !e.orderFrom(args) {
+		return
+	}
+	children := e.curr.Children()
+	sort.SliceStable(children, func(i, j int) bool {
+		if e.order == mcts.HighestValue {
+			return children[i].Value() > children[j].Value()
+		}
+		return children[i].Visits() > children[j].Visits()
+	})
+	e.listing = children
+	if len(children) == 0 {
+		fmt.Fprintln(e.out, "no children")
+		return
+	}
+	for idx, child := range children {
+		fmt.Fprintf(e.out, "%3d  action: %-12v visits: %-8d value: %-10.4f id: %s\n",
+			idx, child.Action(), child.Visits(), child.Value(), child.ID())
+	}
+}
+
+func (e *Explorer) cd(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(e.out, "usage: cd <n> | cd ..")
+		return
+	}
+	if args[0] == ".." {
+		if e.curr.Parent() == nil {
+			fmt.Fprintln(e.out, "already at root")
+			return
+		}
+		e.curr = e.curr.Parent()
+		e.listing = nil
+		return
+	}
+	idx, err := strconv.Atoi(args[0])
+	if err != nil || idx < 0 || idx >= len(e.listing) {
+		fmt.Fprintf(e.out, "invalid child %q, run ls first\n", args[0])
+		return
+	}
+	e.curr = e.listing[idx]
+	e.listing = nil
+}
+
+func (e *Explorer) pv(args []string) {
+	if !e.orderFrom(args) {
+		return
+	}
+	pv := e.curr.PrincipalVariation(e.order)
+	if len(pv) == 0 {
+		fmt.Fprintln(e.out, "no principal variation")
+		return
+	}
+	for ply, step := range pv {
+		fmt.Fprintf(e.out, "%3d  action: %-12v visits: %-8d value: %-10.4f id: %s\n",
+			ply+1, step.Action, step.Visits, step.Value, step.ID)
+	}
+}
+
+func (e *Explorer) show() {
+	n := e.curr
+	fmt.Fprintf(e.out, "id: %s\naction: %v\ndepth: %d\nvisits: %d\nvalue: %.4f\nchildren: %d\n",
+		n.ID(), n.Action(), n.Depth(), n.Visits(), n.Value(), len(n.Children()))
+	if stringer, ok := n.State().(fmt.Stringer); ok {
+		fmt.Fprintln(e.out, stringer.String())
+	}
+}
@@ -0,0 +1,79 @@
+package explorer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/internal/mctstest"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func searchedTree(t *testing.T) *mcts.MonteCarloTree {
+	tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 100})
+	_, err := tree.Start(mctstest.Countdown{Left: 3})
+	assert.NoError(t, err)
+	return &tree
+}
+
+func TestExplorerNavigation(t *testing.T) {
+	tree := searchedTree(t)
+	out := &bytes.Buffer{}
+	e := New(tree.Root(), out)
+
+	assert.True(t, e.Exec("ls"))
+	assert.Contains(t, out.String(), "  0  action: ")
+	assert.Contains(t, out.String(), "  1  action: ")
+
+	assert.True(t, e.Exec("cd 0"))
+	assert.Equal(t, tree.Root(), e.Current().Parent())
+	assert.Equal(t, tree.Root().PrincipalVariation(mcts.MostVisited)[0].ID, e.Current().ID())
+
+	out.Reset()
+	assert.True(t, e.Exec("show"))
+	assert.Contains(t, out.String(), "counter at ")
+
+	assert.True(t, e.Exec("cd .."))
+	assert.Equal(t, tree.Root(), e.Current())
+
+	out.Reset()
+	assert.True(t, e.Exec("cd .."))
+	assert.Contains(t, out.String(), "already at root")
+
+	out.Reset()
+	assert.True(t, e.Exec("cd 7"))
+	assert.Contains(t, out.String(), "invalid child")
+
+	out.Reset()
+	assert.True(t, e.Exec("pv value"))
+	assert.Contains(t, out.String(), "  1  action: ")
+
+	assert.False(t, e.Exec("quit"))
+}
+
+func TestRun(t *testing.T) {
+	tree := searchedTree(t)
+	out := &bytes.Buffer{}
+	err := Run(tree.Root(), strings.NewReader("ls\ncd 0\nfoo\nquit\nls\n"), out)
+	assert.NoError(t, err)
+
+	action := tree.Root().PrincipalVariation(mcts.MostVisited)[0].Action
+	assert.Contains(t, out.String(), fmt.Sprintf("[/%v]> ", action))
+	assert.Contains(t, out.String(), `unknown command "foo"`)
+	assert.Equal(t, 4, strings.Count(out.String(), "]> "))
+}
+
+func TestRunLoadedTree(t *testing.T) {
+	tree := searchedTree(t)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, nil))
+
+	loaded := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{})
+	assert.NoError(t, loaded.Load(buf, nil, nil))
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, Run(loaded.Root(), strings.NewReader("ls\ncd 0\nshow\n"), out))
+	assert.Contains(t, out.String(), "id: left-")
+	assert.NotContains(t, out.String(), "counter at")
+}
@@ -0,0 +1,100 @@
+package mcts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ExportOptions prunes the exported tree.
+type ExportOptions struct {
+	// MinVisits skips nodes visited less than MinVisits times.
+	MinVisits uint
+	// MaxDepth skips nodes deeper than MaxDepth below the root, zero means no limit.
+	MaxDepth int
+}
+
+// ExportedNode is the JSON representation of a node.
+type ExportedNode struct {
+	ID     string  `json:"id"`
+	Action string  `json:"action,omitempty"`
+	Visits uint    `json:"visits"`
+	Value  float64 `json:"value"`
+	// UCB is the selection score of the node from its parent, nil for the root.
+	UCB      *float64       `json:"ucb,omitempty"`
+	Children []ExportedNode `json:"children,omitempty"`
+}
+
+// Export returns the tree as ExportedNode, pruned with opts.
+func (mct *MonteCarloTree) Export(opts ExportOptions) (ExportedNode, error) {
+	if mct.node == nil {
+		return ExportedNode{}, fmt.Errorf("tree has no root, call Start first")
+	}
+	return mct.exportNode(mct.node, 0, opts), nil
+}
+
+// WriteJSON writes the tree as JSON, pruned with opts.
+func (mct *MonteCarloTree) WriteJSON(w io.Writer, opts ExportOptions) error {
+	exported, err := mct.Export(opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exported)
+}
+
+// WriteDOT writes the tree as a Graphviz digraph, pruned with opts.
+func (mct *MonteCarloTree) WriteDOT(w io.Writer, opts ExportOptions) error {
+	exported, err := mct.Export(opts)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph mcts {")
+	fmt.Fprintln(bw, "  node [shape=box];")
+	nextID := 0
+	var write func(n ExportedNode) string
+	write = func(n ExportedNode) string {
+		name := fmt.Sprintf("n%d", nextID)
+		nextID++
+		label := fmt.Sprintf("%s\nvisits: %d\nvalue: %.3f", n.ID, n.Visits, n.Value)
+		if n.UCB != nil {
+			label += fmt.Sprintf("\nucb: %.3f", *n.UCB)
+		}
+		fmt.Fprintf(bw, "  %s [label=%s];\n", name, strconv.Quote(label))
+		for _, child := range n.Children {
+			childName := write(child)
+			fmt.Fprintf(bw, "  %s -> %s [label=%s];\n", name, childName, strconv.Quote(child.Action))
+		}
+		return name
+	}
+	write(exported)
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func (mct *MonteCarloTree) exportNode(n *Node, depth int, opts ExportOptions) ExportedNode {
+	exported := ExportedNode{
+		ID:     n.id,
+		Action: actionLabel(n.action),
+		Visits: n.nVisited,
+		Value:  n.meanScore(),
+	}
+	if n.parent != nil && n != mct.node && n.nVisited > 0 {
+		ucb := mct.selector().score(n)
+		exported.UCB = &ucb
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		return exported
+	}
+	for _, child := range n.child {
+		if child.nVisited < opts.MinVisits {
+			continue
+		}
+		exported.Children = append(exported.Children, mct.exportNode(child, depth+1, opts))
+	}
+	return exported
+}
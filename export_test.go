@@ -0,0 +1,67 @@
+package mcts
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 200})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	exported, err := tree.Export(ExportOptions{MaxDepth: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "0-0", exported.ID)
+	assert.Nil(t, exported.UCB)
+	assert.Equal(t, uint(200), exported.Visits)
+	assert.Len(t, exported.Children, 3)
+	for _, child := range exported.Children {
+		assert.Empty(t, child.Children)
+		assert.NotNil(t, child.UCB)
+	}
+	assert.Equal(t, "5", exported.Children[1].Action)
+	assert.Equal(t, tree.node.child[1].meanScore(), exported.Children[1].Value)
+
+	pruned, err := tree.Export(ExportOptions{MinVisits: tree.node.child[1].nVisited})
+	assert.NoError(t, err)
+	assert.Len(t, pruned.Children, 1)
+	assert.Equal(t, "5", pruned.Children[0].Action)
+}
+
+func TestWriteJSON(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.WriteJSON(buf, ExportOptions{MaxDepth: 2}))
+
+	var exported ExportedNode
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	assert.Equal(t, uint(50), exported.Visits)
+	assert.Len(t, exported.Children, 3)
+}
+
+func TestWriteDOT(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 20})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.WriteDOT(buf, ExportOptions{MaxDepth: 1}))
+	dot := buf.String()
+
+	assert.True(t, strings.HasPrefix(dot, "digraph mcts {"))
+	assert.Contains(t, dot, `n0 [label="0-0\nvisits: 20`)
+	assert.Contains(t, dot, `n0 -> n2 [label="5"];`)
+	assert.Equal(t, 3, strings.Count(dot, "->"))
+}
+
+func TestExportWithoutRoot(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{})
+	assert.Error(t, tree.WriteDOT(&bytes.Buffer{}, ExportOptions{}))
+}
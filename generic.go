@@ -0,0 +1,174 @@
+package mcts
+
+// TypedState is the type safe version of State, S is the state type itself and A its action type.
+type TypedState[S any, A any] interface {
+	Simulate() float64
+	Expand(iter A) S
+	Iterations() []A
+	Copy() S
+	ID() string
+}
+
+// Tree is a MonteCarloTree over typed states and actions.
+type Tree[S TypedState[S, A], A any] struct {
+	tree MonteCarloTree
+}
+
+// TypedScore is the FinalScore of a Tree.
+type TypedScore[S TypedState[S, A], A any] struct {
+	Iterations uint
+	// Moves are the root children sorted by visits.
+	Moves      []TypedMove[S, A]
+	TotalNodes uint
+	StopReason StopReason
+	Stats      SearchStats
+	// BestSequence is the FinalScore.BestSequence, actions of other types are left out.
+	BestSequence []A
+	BestScore    float64
+}
+
+// TypedMove is one root child of a Tree.
+type TypedMove[S TypedState[S, A], A any] struct {
+	State  S
+	Action A
+	Visits uint
+}
+
+// NewTree creates a Tree, config is the same of NewMonteCarloTree.
+func NewTree[S TypedState[S, A], A any](config MonteCarloTreeConfig) *Tree[S, A] {
+	return &Tree[S, A]{tree: NewMonteCarloTree(config)}
+}
+
+// Start searches from initialState, as MonteCarloTree.Start.
+func (t *Tree[S, A]) Start(initialState S) (TypedScore[S, A], error) {
+	res, err := t.tree.Start(typedState[S, A]{state: initialState})
+	return typedScore[S, A](res), err
+}
+
+// Continue searches again from the current root, as MonteCarloTree.Continue.
+func (t *Tree[S, A]) Continue() (TypedScore[S, A], error) {
+	res, err := t.tree.Continue()
+	return typedScore[S, A](res), err
+}
+
+// Advance re-roots the tree to state, as MonteCarloTree.Advance.
+func (t *Tree[S, A]) Advance(state S) error {
+	return t.tree.Advance(typedState[S, A]{state: state})
+}
+
+// Untyped returns the underlying MonteCarloTree, its states are wrapped and
+// can be unwrapped with Unwrap.
+func (t *Tree[S, A]) Untyped() *MonteCarloTree {
+	return &t.tree
+}
+
+// Unwrap returns the typed state of a state from a Tree.
+func Unwrap[S TypedState[S, A], A any](s State) (S, bool) {
+	typed, ok := s.(typedState[S, A])
+	return typed.state, ok
+}
+
+func typedScore[S TypedState[S, A], A any](res FinalScore) TypedScore[S, A] {
+	moves := make([]TypedMove[S, A], 0, len(res.NodeScore))
+	for _, score := range res.NodeScore {
+		move := TypedMove[S, A]{
+			State:  score.State.(typedState[S, A]).state,
+			Visits: score.total,
+		}
+		if action, ok := score.Action.(A); ok {
+			move.Action = action
+		}
+		moves = append(moves, move)
+	}
+	var sequence []A
+	for _, action := range res.BestSequence {
+		if typed, ok := action.(A); ok {
+			sequence = append(sequence, typed)
+		}
+	}
+	return TypedScore[S, A]{
+		Iterations:   res.Iterations,
+		Moves:        moves,
+		TotalNodes:   res.TotalNodes,
+		StopReason:   res.StopReason,
+		Stats:        res.Stats,
+		BestSequence: sequence,
+		BestScore:    res.BestScore,
+	}
+}
+
+// typedState adapts a TypedState to State.
+type typedState[S TypedState[S, A], A any] struct {
+	state S
+}
+
+func (t typedState[S, A]) Simulate() float64 {
+	return t.state.Simulate()
+}
+
+func (t typedState[S, A]) Expand(iter any) State {
+	return typedState[S, A]{state: t.state.Expand(iter.(A))}
+}
+
+func (t typedState[S, A]) Iterations() []any {
+	typed := t.state.Iterations()
+	if typed == nil {
+		return nil
+	}
+	iterations := make([]any, len(typed))
+	for idx, iter := range typed {
+		iterations[idx] = iter
+	}
+	return iterations
+}
+
+func (t typedState[S, A]) Copy() State {
+	return typedState[S, A]{state: t.state.Copy()}
+}
+
+func (t typedState[S, A]) ID() string {
+	return t.state.ID()
+}
+
+func (t typedState[S, A]) CopyPolicy() CopyPolicy {
+	if aware, ok := any(t.state).(CopyAwareState); ok {
+		return aware.CopyPolicy()
+	}
+	return CopyPolicy{}
+}
+
+func (t typedState[S, A]) Size() uint64 {
+	if sized, ok := any(t.state).(SizedState); ok {
+		return sized.Size()
+	}
+	return 0
+}
+
+func (t typedState[S, A]) Terminal() (float64, bool) {
+	if terminal, ok := any(t.state).(TerminalState); ok {
+		return terminal.Terminal()
+	}
+	return 0, false
+}
+
+func (t typedState[S, A]) ExpandReward(iter any) (State, float64) {
+	if rewarding, ok := any(t.state).(interface{ ExpandReward(A) (S, float64) }); ok {
+		state, reward := rewarding.ExpandReward(iter.(A))
+		return typedState[S, A]{state: state}, reward
+	}
+	return t.Expand(iter), 0
+}
+
+func (t typedState[S, A]) SimulateActions() (float64, []any) {
+	if recording, ok := any(t.state).(RecordingState); ok {
+		return recording.SimulateActions()
+	}
+	return t.state.Simulate(), nil
+}
+
+func (t typedState[S, A]) String() string {
+	if stringer, ok := any(t.state).(interface{ String() string }); ok {
+		return stringer.String()
+	}
+	return t.state.ID()
+}
@@ -0,0 +1,60 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// typedSum is sumState with typed actions.
+type typedSum struct {
+	sumState
+}
+
+func (s typedSum) Expand(iter int) typedSum {
+	return typedSum{s.sumState.Expand(iter).(sumState)}
+}
+
+func (s typedSum) Iterations() []int {
+	iters := make([]int, 0)
+	for _, iter := range s.sumState.Iterations() {
+		iters = append(iters, iter.(int))
+	}
+	return iters
+}
+
+func (s typedSum) Copy() typedSum {
+	return s
+}
+
+func (s typedSum) CopyPolicy() CopyPolicy {
+	return CopyPolicy{IterationsReadOnly: true}
+}
+
+func (s typedSum) String() string {
+	return fmt.Sprintf("total %d", s.total)
+}
+
+func TestTypedTree(t *testing.T) {
+	tree := NewTree[typedSum, int](MonteCarloTreeConfig{MaxIterations: 300})
+	res, err := tree.Start(typedSum{newSumState()})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint(300), res.Iterations)
+	assert.Len(t, res.Moves, 3)
+	assert.Equal(t, 5, res.Moves[0].Action)
+	assert.Equal(t, 5, res.Moves[0].State.total)
+	assert.GreaterOrEqual(t, res.Moves[0].Visits, res.Moves[1].Visits)
+
+	root := tree.Untyped().Root()
+	state, ok := Unwrap[typedSum, int](root.Children()[0].State())
+	assert.True(t, ok)
+	assert.Equal(t, 1, state.total)
+	assert.Equal(t, CopyPolicy{IterationsReadOnly: true}, copyPolicyOf(root.State()))
+	assert.Equal(t, "total 0", fmt.Sprint(root.State()))
+
+	assert.NoError(t, tree.Advance(res.Moves[0].State))
+	res, err = tree.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, 10, res.Moves[0].State.total)
+}
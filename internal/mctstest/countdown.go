@@ -0,0 +1,45 @@
+// Package mctstest holds the states shared by the tests of the mcts packages.
+package mctstest
+
+import (
+	"fmt"
+	"github.com/danielsussa/mcts"
+)
+
+// Countdown removes 1 or 2 from the counter, reaching zero exactly scores 1.
+type Countdown struct {
+	Left int
+}
+
+func (c Countdown) Simulate() float64 {
+	if c.Left == 0 {
+		return 1
+	}
+	return 0
+}
+
+func (c Countdown) Expand(iter any) mcts.State {
+	return Countdown{Left: c.Left - iter.(int)}
+}
+
+func (c Countdown) Iterations() []any {
+	iters := make([]any, 0)
+	for _, step := range []int{1, 2} {
+		if c.Left >= step {
+			iters = append(iters, step)
+		}
+	}
+	return iters
+}
+
+func (c Countdown) Copy() mcts.State {
+	return c
+}
+
+func (c Countdown) ID() string {
+	return fmt.Sprintf("left-%d", c.Left)
+}
+
+func (c Countdown) String() string {
+	return fmt.Sprintf("counter at %d", c.Left)
+}
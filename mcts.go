@@ -19,76 +19,89 @@ type Node struct {
 	iterations       []any
 	currIterationIdx int
 	id               string
-}
 
-func (n *Node) rollOut(simConfig SimulationConfig) {
-	score := 0.0
-	switch simConfig.Strategy {
-	case Avg:
-		score = n.avgStrategy(simConfig)
-	case Min:
-		score = n.minStrategy(simConfig)
-	case Max:
-		score = n.maxStrategy(simConfig)
-	default:
-		score = n.avgStrategy(simConfig)
-	}
-	n.backPropagate(score)
+	// action is the iteration that expanded this node from its parent, at actionIdx.
+	action    any
+	actionIdx int
+
+	// amaf holds the RAVE statistics, nil when RAVE is disabled.
+	amaf *amafTable
+
+	// reward is the immediate reward of the transition from the parent, see RewardState.
+	reward float64
+	// value is the MaxBackup or MixedBackup value, when backed is set.
+	value  float64
+	backed bool
+
+	// sumSquares and maxScore describe the backpropagated scores, for SP-MCTS.
+	sumSquares float64
+	maxScore   float64
+
+	// par holds the shared statistics of a parallel search, nil otherwise.
+	par *parallelNode
 }
 
-func (n *Node) avgStrategy(simConfig SimulationConfig) float64 {
-	score := 0.0
-	for i := 0; i <= simConfig.Ratio; i++ {
-		score += n.state.Copy().Simulate()
-	}
-	return score
+// recordedSimulation runs the simulations of a rollout from state, returning the actions
+// they played when record is set, and aggregates their scores.
+func recordedSimulation(state State, simConfig SimulationConfig, record bool) (float64, []any) {
+	scores, runs := simulationRuns(state, simConfig, record)
+	return simConfig.combine(scores, runs, record)
 }
 
-func (n *Node) minStrategy(simConfig SimulationConfig) float64 {
-	minScore := 0.0
-	for i := 0; i <= simConfig.Ratio; i++ {
-		if i == 0 {
-			minScore = n.state.Copy().Simulate()
-			if minScore <= 0 {
-				break
-			}
-		} else {
-			currScore := n.state.Copy().Simulate()
-			if currScore < minScore {
-				minScore = currScore
-			}
+// simulationRuns runs the simulations of a rollout from state, returning the score of every run
+// and the actions it played.
+func simulationRuns(state State, simConfig SimulationConfig, record bool) ([]float64, [][]any) {
+	run := func() (float64, []any) {
+		return simulate(state, record)
+	}
+	if simConfig.drivesRollouts() {
+		run = func() (float64, []any) {
+			return simConfig.playoutCopy(state)
 		}
 	}
-	return minScore
+	scores := make([]float64, simConfig.runs())
+	runs := make([][]any, len(scores))
+	for i := range scores {
+		scores[i], runs[i] = run()
+	}
+	return scores, runs
 }
 
-func (n *Node) maxStrategy(simConfig SimulationConfig) float64 {
-	maxScore := 0.0
-	for i := 0; i <= simConfig.Ratio; i++ {
-		if i == 0 {
-			maxScore = n.state.Copy().Simulate()
-		} else {
-			currScore := n.state.Copy().Simulate()
-			if currScore > maxScore {
-				maxScore = currScore
-			}
+// combine aggregates the scores of the runs of a rollout, with the actions of every run when record is set.
+func (s SimulationConfig) combine(scores []float64, runs [][]any, record bool) (float64, []any) {
+	if len(scores) == 1 && s.Strategy != Custom {
+		return scores[0], runs[0]
+	}
+	var played []any
+	if record {
+		for _, actions := range runs {
+			played = append(played, actions...)
 		}
 	}
-	return maxScore
+	return s.aggregate(scores), played
 }
 
 func (n *Node) backPropagate(score float64) {
-	n.nVisited++
-	n.score += score
+	n.visit(score)
 	if n.parent == nil {
 		return
 	}
 	n.parent.backPropagate(score)
 }
 
+// visit adds score to the statistics of n.
+func (n *Node) visit(score float64) {
+	if n.nVisited == 0 || score > n.maxScore {
+		n.maxScore = score
+	}
+	n.nVisited++
+	n.score += score
+	n.sumSquares += score * score
+}
+
 func (n *Node) expand() (*Node, error) {
 	if n.iterations == nil {
-		iteration := n.state.Copy().Iterations()
+		iteration := n.iterationsOf()
 		if iteration == nil {
 			return nil, fmt.Errorf("iterations return nil")
 		}
@@ -97,7 +110,8 @@ func (n *Node) expand() (*Node, error) {
 	if len(n.iterations) == n.currIterationIdx {
 		return n, nil
 	}
-	state := n.state.Copy().Expand(n.iterations[n.currIterationIdx])
+	actionIdx := n.currIterationIdx
+	state, reward := n.expandReward(n.iterations[actionIdx])
 	n.currIterationIdx++
 	if state == nil {
 		return nil, fmt.Errorf("expand return nil")
@@ -109,6 +123,9 @@ func (n *Node) expand() (*Node, error) {
 		parent:     n,
 		iterations: nil,
 		levelY:     n.levelY + 1,
+		action:     n.iterations[actionIdx],
+		actionIdx:  actionIdx,
+		reward:     reward,
 	}
 	n.child = append(n.child, child)
 	return child, nil
@@ -121,16 +138,22 @@ func (n *Node) getParentNVisited() uint {
 	return n.parent.getParentNVisited()
 }
 
-func (n *Node) selection(policy PolicyFunc) *Node {
+func (n *Node) selection(policy selector) *Node {
+	return n.selectNode(policy, true)
+}
+
+// selectNode descends the tree using policy. When expandable is false, partially
+// expanded nodes are traversed through their existing children instead of being returned.
+func (n *Node) selectNode(policy selector, expandable bool) *Node {
 	if n.child == nil {
 		return n
 	}
-	if n.iterations == nil || n.currIterationIdx < len(n.iterations) {
+	if expandable && (n.iterations == nil || n.currIterationIdx < len(n.iterations)) {
 		return n
 	}
 	selectedNodes := getNodeScore(n, policy)
 	for _, selectedNode := range selectedNodes {
-		node := selectedNode.node.selection(policy)
+		node := selectedNode.node.selectNode(policy, expandable)
 		if node == nil {
 			continue
 		}
@@ -148,13 +171,13 @@ func normalize(val, max, min float64) float64 {
 	return (val - min) / (max - min)
 }
 
-func getNodeScore(parent *Node, policy PolicyFunc) []nodeScore {
+func getNodeScore(parent *Node, policy selector) []nodeScore {
 	nodesScore := make([]nodeScore, 0)
 
 	for _, child := range parent.child {
 		nodesScore = append(nodesScore, nodeScore{
 			node:  child,
-			score: policy(child.score, child.nVisited, child.parent.nVisited),
+			score: policy.score(child),
 		})
 	}
 	sort.SliceStable(nodesScore, func(i, j int) bool {
@@ -195,97 +218,299 @@ type MonteCarloTree struct {
 	totalInteractions uint
 	simulationsConfig SimulationConfig
 	firstStateID      string
+	budget            nodeBudget
+	maxTimeout        *time.Duration
+	progress          ProgressConfig
+	earlyStop         EarlyStopConfig
+	pondering         *pondering
+	timings           searchTimings
+	metrics           Metrics
+	tracer            tracer
+	rave              RAVEConfig
+	mdp               MDPConfig
+	singlePlayer      SinglePlayerConfig
+	best              bestSequence
+	openLoop          bool
+	parallel          ParallelConfig
 }
 
 type FinalScore struct {
 	Iterations uint
 	NodeScore  []nodeFinalScore
+	// TotalNodes is how many nodes this search added to the tree.
 	TotalNodes uint
+	StopReason StopReason
+	Stats      SearchStats
+	// BestSequence is the action sequence of the best simulation found from the root, with SinglePlayer,
+	// and BestScore its score. Every simulation of a rollout counts on its own, and their actions are
+	// included when the tree plays them or the state implements RecordingState.
+	BestSequence []any
+	BestScore    float64
 }
 
 type nodeFinalScore struct {
 	State State
-	total uint
+	// Action is the iteration that leads from the root to State.
+	Action any
+	total  uint
 }
 
 func (mct *MonteCarloTree) Start(initialState State) (FinalScore, error) {
+	if _, err := mct.StopPondering(); err != nil {
+		return FinalScore{}, err
+	}
 	mct.node = &Node{
+		id:         initialState.ID(),
 		state:      initialState.Copy(),
 		iterations: nil,
 	}
+	mct.budget.reset()
+	mct.budget.add(mct.node)
 	return mct.start()
 }
 
 func (mct *MonteCarloTree) start() (FinalScore, error) {
+	if mct.parallel.Workers > 1 {
+		return mct.startParallel()
+	}
 	interactions := uint(0)
 	totalNodes := uint(0)
+	mct.timings = searchTimings{}
+	mct.best = bestSequence{}
+	progress := newProgressTracker(mct.progress)
+	earlyStop := earlyStopTracker{config: mct.earlyStop}
+	stopReason := StopMaxIterations
 	for {
-		node := mct.node.selection(mct.policy)
-
-		childNode, err := node.expand()
+		expanded, err := mct.iterate()
 		if err != nil {
 			return FinalScore{}, err
 		}
-
-		if childNode == nil {
-			node.rollOut(mct.simulationsConfig)
-		} else {
-			childNode.rollOut(mct.simulationsConfig)
+		if expanded {
 			totalNodes++
 		}
 
 		interactions++
-		if interactions >= mct.maxInteractions {
+		if mct.maxInteractions > 0 && interactions >= mct.maxInteractions {
+			stopReason = StopMaxIterations
+			break
+		}
+		if mct.maxTimeout != nil && progress.elapsed() >= *mct.maxTimeout {
+			stopReason = StopTimeout
+			break
+		}
+		if reason, stop := earlyStop.check(mct, interactions); stop {
+			stopReason = reason
+			break
+		}
+		if !progress.report(mct, interactions) {
+			stopReason = StopProgress
 			break
 		}
 	}
+	return mct.finalScore(interactions, totalNodes, stopReason, progress.elapsed()), nil
+}
+
+// finalScore builds the result of a search and reports it to the metrics.
+func (mct *MonteCarloTree) finalScore(interactions, totalNodes uint, stopReason StopReason, elapsed time.Duration) FinalScore {
 	mct.totalInteractions += interactions
 
+	res := FinalScore{
+		Iterations: mct.totalInteractions,
+		TotalNodes: totalNodes,
+		NodeScore:  mct.rootScores(),
+		StopReason: stopReason,
+		Stats:      mct.stats(interactions, elapsed),
+	}
+	if mct.best.found {
+		res.BestSequence = mct.best.actions
+		res.BestScore = mct.best.score
+	}
+	if mct.metrics != nil {
+		mct.metrics.ObserveSearch(res)
+	}
+	return res
+}
+
+// iterate runs one selection, expansion, simulation and backpropagation cycle,
+// returning whether a new node was added to the tree.
+func (mct *MonteCarloTree) iterate() (bool, error) {
+	if mct.budget.limitReached() && mct.budget.policy == PruneColdNodes {
+		mct.budget.prune(mct.node)
+	}
+	if mct.openLoop {
+		return mct.iterateOpenLoop()
+	}
+
+	lap := time.Now()
+	if mct.budget.limitReached() {
+		node := mct.node.selectNode(mct.selector(), false)
+		mct.timings.selection += time.Since(lap)
+		mct.trace(node, false, mct.rollOut(node))
+		return false, nil
+	}
+
+	node := mct.node.selection(mct.selector())
+	mct.timings.selection += time.Since(lap)
+
+	lap = time.Now()
+	childNode, err := node.expand()
+	mct.timings.expansion += time.Since(lap)
+	if err != nil {
+		return false, err
+	}
+
+	if childNode == nil {
+		mct.trace(node, false, mct.rollOut(node))
+		return false, nil
+	}
+	expanded := childNode != node
+	if expanded {
+		mct.budget.add(childNode)
+	}
+	mct.trace(childNode, expanded, mct.rollOut(childNode))
+	return expanded, nil
+}
+
+func (mct *MonteCarloTree) rollOut(n *Node) float64 {
+	return mct.rollOutFrom(n, n.state)
+}
+
+// rollOutFrom simulates state and backpropagates its score from n.
+func (mct *MonteCarloTree) rollOutFrom(n *Node, state State) float64 {
+	lap := time.Now()
+	record := mct.rave.enabled() || mct.singlePlayer.Enabled
+	scores, runs := simulationRuns(state, mct.simulationsConfig, record)
+	score, played := mct.simulationsConfig.combine(scores, runs, record)
+	elapsed := time.Since(lap)
+	mct.timings.simulation += elapsed
+	if mct.metrics != nil {
+		mct.metrics.ObserveRollout(elapsed)
+	}
+
+	lap = time.Now()
+	if mct.mdp.Enabled {
+		mct.backPropagateReturn(n, score)
+	} else {
+		n.backPropagate(score)
+	}
+	if mct.singlePlayer.Enabled {
+		// each run is a sequence of its own, none of them may reach the aggregated score
+		for i, runScore := range scores {
+			mct.best.record(mct.node, n, runs[i], mct.rootReturn(n, runScore))
+		}
+	}
+	if mct.rave.enabled() {
+		mct.updateAMAF(n, played, score)
+	}
+	mct.timings.backPropagation += time.Since(lap)
+	mct.timings.rollouts++
+	return score
+}
+
+func (mct *MonteCarloTree) rootScores() []nodeFinalScore {
 	ndScore := make([]nodeFinalScore, 0)
 	for _, childNode := range mct.node.child {
+		state := childNode.state
+		if state == nil && mct.node.state != nil {
+			state = mct.node.expandState(childNode.action)
+		}
 		ndScore = append(ndScore, nodeFinalScore{
-			total: childNode.nVisited,
-			State: childNode.state,
+			total:  childNode.nVisited,
+			State:  state,
+			Action: childNode.action,
 		})
 	}
 
 	sort.SliceStable(ndScore, func(i, j int) bool {
 		return ndScore[i].total > ndScore[j].total
 	})
-
-	return FinalScore{
-		Iterations: mct.totalInteractions,
-		TotalNodes: totalNodes,
-		NodeScore:  ndScore,
-	}, nil
+	return ndScore
 }
 
 type MonteCarloTreeConfig struct {
-	MaxTimeout       *time.Duration
-	MaxIterations    uint
+	MaxTimeout    *time.Duration
+	MaxIterations uint
+	// MaxNodes limits how many nodes the tree can hold, zero means no limit.
+	MaxNodes uint
+	// MaxMemory limits the approximate tree size in bytes, zero means no limit.
+	// States implementing SizedState have their size accounted too.
+	MaxMemory uint64
+	// NodeLimitPolicy is applied once MaxNodes or MaxMemory is reached, StopExpanding by default.
+	NodeLimitPolicy  NodeLimitPolicy
 	SimulationConfig SimulationConfig
+	// Progress reports snapshots of the search while it runs.
+	Progress ProgressConfig
+	// EarlyStop ends the search once the best move is settled.
+	EarlyStop EarlyStopConfig
+	// Metrics is optional and receives measurements of every search.
+	Metrics Metrics
+	// Trace logs the search iterations, it's disabled without a Logger.
+	Trace TraceConfig
+	// RAVE blends AMAF statistics into selection, it's disabled by default.
+	RAVE RAVEConfig
+	// MDP backs up discounted returns of transition rewards, it's disabled by default.
+	MDP MDPConfig
+	// SinglePlayer selects with SP-MCTS and keeps the best sequence found, it's disabled by default.
+	SinglePlayer SinglePlayerConfig
+	// OpenLoop keeps only action statistics in the nodes below the root and expands the state
+	// again on every descent, for games where Expand is stochastic. The root children states
+	// in FinalScore are one sample of their action.
+	OpenLoop bool
+	// Parallel searches the tree with several goroutines, it's sequential by default.
+	Parallel ParallelConfig
 }
 
 type SimulationConfig struct {
-	Ratio    int
+	// Ratio is how many simulations run on each rollout, values below 1 run a single simulation.
+	Ratio int
+	// Strategy aggregates the scores of the Ratio simulations, Avg by default.
 	Strategy ScoreStrategy
+	// Trim is the fraction of scores dropped from each end by TrimmedMean, 0.1 by default.
+	Trim float64
+	// Level is the fraction used by Quantile and CVaR, 0.1 by default.
+	Level float64
+	// Aggregate combines the scores when Strategy is Custom.
+	Aggregate func(scores []float64) float64
+	// Policy makes the tree play the rollouts itself instead of calling State.Simulate,
+	// see Playout. Setting MaxDepth or Discount plays them with RandomPolicy by default.
+	Policy RolloutPolicy
+	// MaxDepth cuts the rollouts played by the tree after MaxDepth moves, zero means no limit.
+	MaxDepth int
+	// Evaluate scores the state at the MaxDepth cutoff, State.Simulate by default.
+	Evaluate func(state State) float64
+	// Discount multiplies the rollout reward once per move played, values outside (0, 1) don't discount.
+	Discount float64
 }
 
-type ScoreStrategy string
-
-const (
-	Min ScoreStrategy = "min"
-	Max ScoreStrategy = "max"
-	Avg ScoreStrategy = "avg"
-)
+func (s SimulationConfig) drivesRollouts() bool {
+	return s.Policy != nil || s.MaxDepth > 0 || (s.Discount > 0 && s.Discount < 1)
+}
 
 func NewMonteCarloTree(config MonteCarloTreeConfig) MonteCarloTree {
 	if config.MaxIterations == 0 && config.MaxTimeout == nil {
 		config.MaxIterations = 1000
 	}
+	if config.NodeLimitPolicy == "" {
+		config.NodeLimitPolicy = StopExpanding
+	}
 	return MonteCarloTree{
 		policy:            defaultPolicyFunc(),
 		maxInteractions:   config.MaxIterations,
+		maxTimeout:        config.MaxTimeout,
 		simulationsConfig: config.SimulationConfig,
+		progress:          config.Progress,
+		earlyStop:         config.EarlyStop,
+		metrics:           config.Metrics,
+		tracer:            tracer{config: config.Trace},
+		rave:              config.RAVE,
+		mdp:               config.MDP,
+		singlePlayer:      config.SinglePlayer,
+		openLoop:          config.OpenLoop,
+		parallel:          config.Parallel,
+		budget: nodeBudget{
+			maxNodes:  config.MaxNodes,
+			maxMemory: config.MaxMemory,
+			policy:    config.NodeLimitPolicy,
+		},
 	}
 }
@@ -1,6 +1,7 @@
 package mcts
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -127,3 +128,48 @@ func TestNodeSelection2(t *testing.T) {
 func TestNormalize(t *testing.T) {
 	assert.Equal(t, 0.5, normalize(6, 3, 9))
 }
+
+// sumState is a deterministic game where each move adds its value to the total,
+// the best line always picks the highest move.
+type sumState struct {
+	total int
+	depth int
+	moves []int
+}
+
+func (s sumState) Simulate() float64 {
+	total := s.total
+	for d := s.depth; d < 4; d++ {
+		total += s.moves[d%len(s.moves)]
+	}
+	return float64(total) / 100
+}
+
+func (s sumState) Expand(iter any) State {
+	s.total += iter.(int)
+	s.depth++
+	return s
+}
+
+func (s sumState) Iterations() []any {
+	if s.depth >= 4 {
+		return []any{}
+	}
+	iters := make([]any, 0, len(s.moves))
+	for _, m := range s.moves {
+		iters = append(iters, m)
+	}
+	return iters
+}
+
+func (s sumState) Copy() State {
+	return s
+}
+
+func (s sumState) ID() string {
+	return fmt.Sprintf("%d-%d", s.depth, s.total)
+}
+
+func newSumState() sumState {
+	return sumState{moves: []int{1, 5, 3}}
+}
@@ -0,0 +1,109 @@
+package mcts
+
+import "math"
+
+// RewardState can be implemented by a State to report the immediate reward of its transitions.
+type RewardState interface {
+	// ExpandReward is Expand returning the reward of playing iter too.
+	ExpandReward(iter any) (State, float64)
+}
+
+// BackupMode tells how the values of the children are backed up to their parent.
+type BackupMode string
+
+const (
+	// MeanBackup averages the discounted returns of every visit.
+	MeanBackup BackupMode = "mean"
+	// MaxBackup backs up the best child value, for single player optimization.
+	MaxBackup BackupMode = "max"
+	// MixedBackup mixes the mean and max backups with MDPConfig.Mix.
+	MixedBackup BackupMode = "mixed"
+)
+
+const defaultMix = 0.5
+
+// MDPConfig turns the search into single agent planning: nodes accumulate the discounted
+// returns of the rewards reported by RewardState transitions plus the rollout score.
+// Rollouts played by the tree sum the transition rewards too, discounted by SimulationConfig.Discount.
+type MDPConfig struct {
+	Enabled bool
+	// Gamma discounts the return once per transition, values outside (0, 1) don't discount.
+	Gamma float64
+	// Backup is MeanBackup by default.
+	Backup BackupMode
+	// Mix is the weight of the mean in MixedBackup, 0.5 by default.
+	Mix float64
+}
+
+func (m MDPConfig) gamma() float64 {
+	if m.Gamma <= 0 || m.Gamma >= 1 {
+		return 1
+	}
+	return m.Gamma
+}
+
+// backPropagateReturn backs up the rollout score of n as discounted returns, each node
+// accumulates the return of the transition that reached it and the root the return of its state,
+// which is returned.
+func (mct *MonteCarloTree) backPropagateReturn(n *Node, score float64) float64 {
+	gamma := mct.mdp.gamma()
+	ret := score
+	for curr := n; curr != nil; curr = curr.parent {
+		if curr.parent != nil {
+			ret = curr.reward + gamma*ret
+		}
+		curr.visit(ret)
+		if mct.mdp.Backup == MaxBackup || mct.mdp.Backup == MixedBackup {
+			mct.backUp(curr)
+		}
+	}
+	return ret
+}
+
+// rootReturn is the return at the root of a rollout of n scoring score.
+func (mct *MonteCarloTree) rootReturn(n *Node, score float64) float64 {
+	if !mct.mdp.Enabled {
+		return score
+	}
+	ret := score
+	for curr := n; curr.parent != nil; curr = curr.parent {
+		ret = curr.reward + mct.mdp.gamma()*ret
+	}
+	return ret
+}
+
+// backUp updates the backed up value of n from its best child.
+func (mct *MonteCarloTree) backUp(n *Node) {
+	mean := n.score / float64(n.nVisited)
+	best := math.Inf(-1)
+	for _, child := range n.child {
+		if child.nVisited > 0 {
+			best = math.Max(best, child.backedValue())
+		}
+	}
+	n.backed = true
+	if math.IsInf(best, -1) {
+		n.value = mean
+		return
+	}
+	if n.parent != nil {
+		best = n.reward + mct.mdp.gamma()*best
+	}
+	if mct.mdp.Backup == MaxBackup {
+		n.value = best
+		return
+	}
+	mix := mct.mdp.Mix
+	if mix <= 0 || mix > 1 {
+		mix = defaultMix
+	}
+	n.value = mix*mean + (1-mix)*best
+}
+
+// backedValue is the value backed up by MaxBackup or MixedBackup, the mean otherwise.
+func (n *Node) backedValue() float64 {
+	if n.backed {
+		return n.value
+	}
+	return n.score / float64(n.nVisited)
+}
@@ -0,0 +1,101 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// delayedState rewards 1 right away on "now", or 10 one step later on "later".
+type delayedState struct {
+	path string
+}
+
+func (d delayedState) Simulate() float64 {
+	return 0
+}
+
+func (d delayedState) Expand(iter any) State {
+	state, _ := d.ExpandReward(iter)
+	return state
+}
+
+func (d delayedState) ExpandReward(iter any) (State, float64) {
+	next := delayedState{path: d.path + iter.(string)}
+	switch next.path {
+	case "now":
+		return next, 1
+	case "laterwait":
+		return next, 10
+	}
+	return next, 0
+}
+
+func (d delayedState) Iterations() []any {
+	switch d.path {
+	case "":
+		return []any{"now", "later"}
+	case "later":
+		return []any{"wait"}
+	}
+	return []any{}
+}
+
+func (d delayedState) Copy() State {
+	return d
+}
+
+func (d delayedState) ID() string {
+	return fmt.Sprintf("path-%s", d.path)
+}
+
+func TestMDPDiscount(t *testing.T) {
+	best := func(mdp MDPConfig) string {
+		tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 100, MDP: mdp})
+		res, err := tree.Start(delayedState{})
+		assert.NoError(t, err)
+		return res.NodeScore[0].Action.(string)
+	}
+
+	assert.Equal(t, "later", best(MDPConfig{Enabled: true, Gamma: 0.5}))
+	assert.Equal(t, "now", best(MDPConfig{Enabled: true, Gamma: 0.05}))
+	assert.Equal(t, "later", best(MDPConfig{Enabled: true, Gamma: 0.5, Backup: MaxBackup}))
+	assert.Equal(t, "now", best(MDPConfig{Enabled: true, Gamma: 0.05, Backup: MixedBackup}))
+}
+
+func TestBackPropagateReturn(t *testing.T) {
+	root := &Node{}
+	child := &Node{parent: root, reward: 1}
+	leaf := &Node{parent: child, reward: 2}
+	root.child = []*Node{child}
+	child.child = []*Node{leaf}
+	tree := MonteCarloTree{node: root, mdp: MDPConfig{Enabled: true, Gamma: 0.5, Backup: MaxBackup}}
+
+	tree.backPropagateReturn(leaf, 4)
+	// 2 + 0.5 * 4
+	assert.Equal(t, 4.0, leaf.score)
+	// 1 + 0.5 * 4
+	assert.Equal(t, 3.0, child.score)
+	assert.Equal(t, 3.0, root.score)
+	assert.Equal(t, 3.0, root.value)
+
+	sibling := &Node{parent: child, reward: 0}
+	child.child = append(child.child, sibling)
+	tree.backPropagateReturn(sibling, 0)
+	// 3 and 1 + 0.5 * 0
+	assert.Equal(t, 4.0, child.score)
+	// the max backup keeps the best child
+	assert.Equal(t, 3.0, child.value)
+	assert.Equal(t, 3.0*2, child.selectionTotal())
+}
+
+func TestPlayoutRewards(t *testing.T) {
+	later := EpsilonGreedyPolicy{Heuristic: func(_ State, action any) float64 {
+		if action == "later" {
+			return 1
+		}
+		return 0
+	}}
+	assert.Equal(t, 10.0, Playout(delayedState{}, later))
+	assert.Equal(t, 5.0, SimulationConfig{Policy: later, Discount: 0.5}.Playout(delayedState{}))
+}
@@ -0,0 +1,14 @@
+package mcts
+
+import (
+	"time"
+)
+
+// Metrics receives measurements from the searches of a tree.
+// The metrics package provides an implementation exposed through expvar and Prometheus.
+type Metrics interface {
+	// ObserveRollout is called after each rollout with the time spent simulating.
+	ObserveRollout(d time.Duration)
+	// ObserveSearch is called when Start or Continue returns.
+	ObserveSearch(res FinalScore)
+}
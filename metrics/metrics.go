@@ -0,0 +1,184 @@
+// Package metrics collects measurements of searches and exposes them through expvar
+// and the Prometheus text format, without depending on any client library.
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/danielsussa/mcts"
+)
+
+// DefaultBuckets are the upper bounds in seconds used by the histograms.
+var DefaultBuckets = []float64{0.00001, 0.0001, 0.001, 0.01, 0.1, 0.5, 1, 5, 10, 60}
+
+// Recorder implements mcts.Metrics and is safe to share between trees.
+type Recorder struct {
+	mu sync.Mutex
+
+	searches    uint64
+	iterations  uint64
+	nodes       uint64
+	stopReasons map[mcts.StopReason]uint64
+
+	rolloutLatency *histogram
+	searchDuration *histogram
+}
+
+var _ mcts.Metrics = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder with DefaultBuckets.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		stopReasons:    make(map[mcts.StopReason]uint64),
+		rolloutLatency: newHistogram(DefaultBuckets),
+		searchDuration: newHistogram(DefaultBuckets),
+	}
+}
+
+// ObserveRollout implements mcts.Metrics.
+func (r *Recorder) ObserveRollout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rolloutLatency.observe(d.Seconds())
+}
+
+// ObserveSearch implements mcts.Metrics.
+func (r *Recorder) ObserveSearch(res mcts.FinalScore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.searches++
+	r.iterations += uint64(res.Stats.Iterations)
+	r.nodes += uint64(res.TotalNodes)
+	r.stopReasons[res.StopReason]++
+	r.searchDuration.observe(res.Stats.Duration.Seconds())
+}
+
+// Snapshot returns the current values as a map, as published to expvar.
+func (r *Recorder) Snapshot() map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stopReasons := make(map[string]uint64, len(r.stopReasons))
+	for reason, total := range r.stopReasons {
+		stopReasons[string(reason)] = total
+	}
+	return map[string]any{
+		"searches":                r.searches,
+		"iterations":              r.iterations,
+		"nodes":                   r.nodes,
+		"stop_reasons":            stopReasons,
+		"rollout_latency_seconds": r.rolloutLatency.snapshot(),
+		"search_duration_seconds": r.searchDuration.snapshot(),
+	}
+}
+
+// PublishExpvar publishes the recorder under name, it panics if name is already published.
+func (r *Recorder) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return r.Snapshot()
+	}))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		_ = r.WritePrometheus(w)
+	})
+}
+
+// WritePrometheus writes the metrics in the Prometheus text format.
+func (r *Recorder) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pw := &promWriter{w: w}
+	pw.counter("mcts_searches_total", "Searches completed.", r.searches)
+	pw.counter("mcts_iterations_total", "Iterations run by the searches.", r.iterations)
+	pw.counter("mcts_nodes_total", "Nodes added to the trees.", r.nodes)
+
+	pw.printf("# HELP mcts_stop_reasons_total Searches completed by stop reason.\n")
+	pw.printf("# TYPE mcts_stop_reasons_total counter\n")
+	reasons := make([]string, 0, len(r.stopReasons))
+	for reason := range r.stopReasons {
+		reasons = append(reasons, string(reason))
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		pw.printf("mcts_stop_reasons_total{reason=%q} %d\n", reason, r.stopReasons[mcts.StopReason(reason)])
+	}
+
+	pw.histogram("mcts_rollout_latency_seconds", "Time spent simulating each rollout.", r.rolloutLatency)
+	pw.histogram("mcts_search_duration_seconds", "Duration of the searches.", r.searchDuration)
+	return pw.err
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	h.count++
+	h.sum += v
+	for idx, bound := range h.buckets {
+		if v <= bound {
+			h.counts[idx]++
+		}
+	}
+}
+
+func (h *histogram) snapshot() map[string]any {
+	buckets := make(map[string]uint64, len(h.buckets))
+	for idx, bound := range h.buckets {
+		buckets[fmt.Sprint(bound)] = h.counts[idx]
+	}
+	return map[string]any{
+		"count":   h.count,
+		"sum":     h.sum,
+		"buckets": buckets,
+	}
+}
+
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func (p *promWriter) counter(name, help string, value uint64) {
+	p.printf("# HELP %s %s\n", name, help)
+	p.printf("# TYPE %s counter\n", name)
+	p.printf("%s %d\n", name, value)
+}
+
+func (p *promWriter) histogram(name, help string, h *histogram) {
+	p.printf("# HELP %s %s\n", name, help)
+	p.printf("# TYPE %s histogram\n", name)
+	for idx, bound := range h.buckets {
+		p.printf("%s_bucket{le=\"%g\"} %d\n", name, bound, h.counts[idx])
+	}
+	p.printf("%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	p.printf("%s_sum %g\n", name, h.sum)
+	p.printf("%s_count %d\n", name, h.count)
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/internal/mctstest"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	tree := mcts.NewMonteCarloTree(mcts.MonteCarloTreeConfig{MaxIterations: 50, Metrics: recorder})
+	res, err := tree.Start(mctstest.Countdown{Left: 4})
+	assert.NoError(t, err)
+	res2, err := tree.Continue()
+	assert.NoError(t, err)
+
+	snapshot := recorder.Snapshot()
+	assert.Equal(t, uint64(2), snapshot["searches"])
+	assert.Equal(t, uint64(100), snapshot["iterations"])
+	assert.Equal(t, uint64(res.TotalNodes+res2.TotalNodes), snapshot["nodes"])
+	assert.Equal(t, map[string]uint64{"max_iterations": 2}, snapshot["stop_reasons"])
+	assert.Equal(t, uint64(100), snapshot["rollout_latency_seconds"].(map[string]any)["count"])
+}
+
+func TestHistogram(t *testing.T) {
+	h := newHistogram([]float64{1, 5})
+	h.observe(0.5)
+	h.observe(3)
+	h.observe(7)
+
+	assert.Equal(t, []uint64{1, 2}, h.counts)
+	assert.Equal(t, uint64(3), h.count)
+	assert.Equal(t, 10.5, h.sum)
+}
+
+func TestHandler(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.ObserveRollout(2 * time.Millisecond)
+	recorder.ObserveSearch(mcts.FinalScore{
+		TotalNodes: 4,
+		StopReason: mcts.StopStable,
+		Stats:      mcts.SearchStats{Iterations: 10, Duration: time.Second},
+	})
+
+	rec := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "# TYPE mcts_searches_total counter\nmcts_searches_total 1\n")
+	assert.Contains(t, body, "mcts_iterations_total 10\n")
+	assert.Contains(t, body, "mcts_nodes_total 4\n")
+	assert.Contains(t, body, `mcts_stop_reasons_total{reason="stable"} 1`)
+	assert.Contains(t, body, `mcts_rollout_latency_seconds_bucket{le="0.001"} 0`)
+	assert.Contains(t, body, `mcts_rollout_latency_seconds_bucket{le="0.01"} 1`)
+	assert.Contains(t, body, `mcts_search_duration_seconds_bucket{le="+Inf"} 1`)
+	assert.Contains(t, body, "mcts_search_duration_seconds_sum 1\n")
+}
+
+func TestPublishExpvar(t *testing.T) {
+	recorder := NewRecorder()
+	recorder.ObserveSearch(mcts.FinalScore{StopReason: mcts.StopTimeout})
+	// expvar names can't be published twice, so every run of the test uses its own
+	name := fmt.Sprintf("mcts_test_%d", time.Now().UnixNano())
+	recorder.PublishExpvar(name)
+
+	var published map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &published))
+	assert.Equal(t, 1.0, published["searches"])
+}
@@ -0,0 +1,298 @@
+package mcts
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Searcher is a search algorithm, it's implemented by MonteCarloTree, NestedMonteCarlo and NRPA
+// so they can be benchmarked against each other.
+type Searcher interface {
+	Start(initialState State) (FinalScore, error)
+}
+
+// NestedConfig configures NestedMonteCarlo and NRPA.
+type NestedConfig struct {
+	// Level is the nesting level, 1 by default for NestedMonteCarlo and 2 for NRPA.
+	Level int
+	// Iterations is how many times NRPA searches each level, 100 by default.
+	Iterations int
+	// Alpha is the learning rate of NRPA, 1 by default.
+	Alpha float64
+	// Code identifies an action played from a state in the NRPA policy, the printed action by default.
+	Code func(state State, action any) string
+	// MaxTimeout stops the search returning the best sequence found so far.
+	MaxTimeout *time.Duration
+	// SimulationConfig plays the level 0 rollouts. NRPA replaces its Policy with the learnt one
+	// and NestedMonteCarlo plays RandomPolicy when it's nil.
+	SimulationConfig SimulationConfig
+}
+
+// NestedMonteCarlo is Nested Monte Carlo Search: at each step it plays every action followed by
+// a search of the level below and keeps the best sequence found. The level 0 search is a rollout.
+type NestedMonteCarlo struct {
+	config NestedConfig
+}
+
+// NewNestedMonteCarlo creates a NestedMonteCarlo search.
+func NewNestedMonteCarlo(config NestedConfig) *NestedMonteCarlo {
+	if config.Level < 1 {
+		config.Level = 1
+	}
+	return &NestedMonteCarlo{config: config}
+}
+
+// Start searches the best sequence from initialState, which is returned in FinalScore.BestSequence.
+// NodeScore holds the first move of the sequence.
+func (nm *NestedMonteCarlo) Start(initialState State) (FinalScore, error) {
+	search := newNestedSearch(nm.config)
+	score, sequence, err := search.nmcs(initialState.Copy(), nm.config.Level)
+	if err != nil {
+		return FinalScore{}, err
+	}
+	return search.finalScore(initialState, score, sequence)
+}
+
+// nmcs returns the best score and sequence found from state, which is modified.
+func (s *nestedSearch) nmcs(state State, level int) (float64, []any, error) {
+	if level == 0 {
+		score, played := s.rollout(state, s.config.SimulationConfig)
+		return score, played, nil
+	}
+	best := math.Inf(-1)
+	var bestSequence []any
+	played := make([]any, 0)
+	earned, factor := 0.0, 1.0
+	for !s.timedOut() {
+		actions, score, over := outcome(state)
+		if over {
+			if bestSequence == nil {
+				best = earned + factor*score
+			}
+			break
+		}
+		var stepAction any
+		stepBest := math.Inf(-1)
+		for idx, action := range actions {
+			next, reward := transition(state.Copy(), action)
+			if next == nil {
+				return 0, nil, fmt.Errorf("expand return nil")
+			}
+			score, sequence, err := s.nmcs(next, level-1)
+			if err != nil {
+				return 0, nil, err
+			}
+			score = earned + factor*(reward+s.discount*score)
+			if idx == 0 || score > stepBest {
+				stepAction, stepBest = action, score
+			}
+			if bestSequence == nil || score > best {
+				best = score
+				bestSequence = append(append(append([]any{}, played...), action), sequence...)
+			}
+		}
+		// the best sequence ends before this step when its rollout was cut by MaxDepth
+		// or a stochastic Expand, the best action of this step is played instead
+		action := stepAction
+		if len(bestSequence) > len(played) {
+			action = bestSequence[len(played)]
+		}
+		next, reward := transition(state, action)
+		if next == nil {
+			return 0, nil, fmt.Errorf("expand return nil")
+		}
+		earned += factor * reward
+		factor *= s.discount
+		played = append(played, action)
+		state = next
+	}
+	if bestSequence == nil {
+		bestSequence = played
+	}
+	return best, bestSequence, nil
+}
+
+// NRPA is Nested Rollout Policy Adaptation: each level searches the level below Iterations times
+// and adapts a Gibbs rollout policy towards the best sequence found. The level 0 search is a rollout
+// with that policy.
+type NRPA struct {
+	config NestedConfig
+}
+
+// NewNRPA creates a NRPA search.
+func NewNRPA(config NestedConfig) *NRPA {
+	if config.Level < 1 {
+		config.Level = 2
+	}
+	if config.Iterations < 1 {
+		config.Iterations = 100
+	}
+	if config.Alpha <= 0 {
+		config.Alpha = 1
+	}
+	if config.Code == nil {
+		config.Code = func(_ State, action any) string {
+			return actionLabel(action)
+		}
+	}
+	return &NRPA{config: config}
+}
+
+// Start searches the best sequence from initialState, which is returned in FinalScore.BestSequence.
+// NodeScore holds the first move of the sequence.
+func (nr *NRPA) Start(initialState State) (FinalScore, error) {
+	search := newNestedSearch(nr.config)
+	score, sequence := search.nrpa(initialState, nr.config.Level, make(map[string]float64))
+	return search.finalScore(initialState, score, sequence)
+}
+
+func (s *nestedSearch) nrpa(root State, level int, weights map[string]float64) (float64, []any) {
+	if level == 0 {
+		simConfig := s.config.SimulationConfig
+		simConfig.Policy = gibbsPolicy{weights: weights, code: s.config.Code}
+		return s.rollout(root.Copy(), simConfig)
+	}
+	best := math.Inf(-1)
+	var bestSequence []any
+	for i := 0; i < s.config.Iterations && !s.timedOut(); i++ {
+		score, sequence := s.nrpa(root, level-1, copyWeights(weights))
+		if bestSequence == nil || score >= best {
+			best, bestSequence = score, sequence
+		}
+		weights = s.adapt(root, weights, bestSequence)
+	}
+	return best, bestSequence
+}
+
+// adapt returns weights moved by Alpha towards playing sequence from root.
+func (s *nestedSearch) adapt(root State, weights map[string]float64, sequence []any) map[string]float64 {
+	adapted := copyWeights(weights)
+	state := root.Copy()
+	for _, action := range sequence {
+		actions := state.Iterations()
+		total := 0.0
+		for _, other := range actions {
+			total += math.Exp(weights[s.config.Code(state, other)])
+		}
+		adapted[s.config.Code(state, action)] += s.config.Alpha
+		for _, other := range actions {
+			code := s.config.Code(state, other)
+			adapted[code] -= s.config.Alpha * math.Exp(weights[code]) / total
+		}
+		state = state.Expand(action)
+		if state == nil {
+			break
+		}
+	}
+	return adapted
+}
+
+func copyWeights(weights map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(weights))
+	for code, weight := range weights {
+		copied[code] = weight
+	}
+	return copied
+}
+
+// gibbsPolicy plays actions with a probability proportional to exp of their weight.
+type gibbsPolicy struct {
+	weights map[string]float64
+	code    func(state State, action any) string
+}
+
+func (g gibbsPolicy) Choose(state State, actions []any) int {
+	weights := make([]float64, len(actions))
+	for idx, action := range actions {
+		weights[idx] = g.weights[g.code(state, action)]
+	}
+	return gibbsIndex(weights)
+}
+
+// nestedSearch holds the counters of a NestedMonteCarlo or NRPA search.
+type nestedSearch struct {
+	config   NestedConfig
+	discount float64
+	started  time.Time
+	rollouts uint
+	elapsed  time.Duration
+	timeout  bool
+}
+
+func newNestedSearch(config NestedConfig) *nestedSearch {
+	return &nestedSearch{config: config, discount: config.SimulationConfig.discount(1), started: time.Now()}
+}
+
+func (s *nestedSearch) rollout(state State, simConfig SimulationConfig) (float64, []any) {
+	lap := time.Now()
+	score, played := simConfig.playout(state)
+	s.elapsed += time.Since(lap)
+	s.rollouts++
+	return score, played
+}
+
+func (s *nestedSearch) timedOut() bool {
+	if s.config.MaxTimeout != nil && time.Since(s.started) >= *s.config.MaxTimeout {
+		s.timeout = true
+	}
+	return s.timeout
+}
+
+func (s *nestedSearch) finalScore(initialState State, score float64, sequence []any) (FinalScore, error) {
+	duration := time.Since(s.started)
+	res := FinalScore{
+		Iterations:   s.rollouts,
+		StopReason:   StopMaxIterations,
+		BestSequence: sequence,
+		Stats: SearchStats{
+			Iterations: s.rollouts,
+			Rollouts:   s.rollouts,
+			Duration:   duration,
+			Simulation: s.elapsed,
+		},
+	}
+	if s.timeout {
+		res.StopReason = StopTimeout
+	}
+	// no sequence was scored when the search timed out right away
+	if !math.IsInf(score, -1) {
+		res.BestScore = score
+	}
+	if s.rollouts > 0 {
+		res.Stats.AvgRolloutCost = s.elapsed / time.Duration(s.rollouts)
+	}
+	if duration > 0 {
+		res.Stats.IterationsPerSecond = float64(s.rollouts) / duration.Seconds()
+	}
+	if len(sequence) > 0 {
+		state := initialState.Copy().Expand(sequence[0])
+		if state == nil {
+			return FinalScore{}, fmt.Errorf("expand return nil")
+		}
+		res.NodeScore = []nodeFinalScore{{State: state, Action: sequence[0], total: s.rollouts}}
+	}
+	return res, nil
+}
+
+// outcome returns the iterations of state, or its score when the game is over.
+func outcome(state State) ([]any, float64, bool) {
+	if terminal, ok := state.(TerminalState); ok {
+		if score, over := terminal.Terminal(); over {
+			return nil, score, true
+		}
+	}
+	actions := state.Iterations()
+	if len(actions) == 0 {
+		return nil, state.Simulate(), true
+	}
+	return actions, 0, false
+}
+
+// transition expands action with the reward of RewardState, zero for other states.
+func transition(state State, action any) (State, float64) {
+	if rewarding, ok := state.(RewardState); ok {
+		return rewarding.ExpandReward(action)
+	}
+	return state.Expand(action), 0
+}
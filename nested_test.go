@@ -0,0 +1,77 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestNestedMonteCarlo(t *testing.T) {
+	rand.Seed(1)
+	res, err := NewNestedMonteCarlo(NestedConfig{Level: 2}).Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, []any{5, 5, 5, 5}, res.BestSequence)
+	assert.Equal(t, 0.2, res.BestScore)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+	assert.Equal(t, 5, res.NodeScore[0].Action)
+	assert.Equal(t, StopMaxIterations, res.StopReason)
+	assert.Greater(t, res.Iterations, uint(20))
+}
+
+func TestNestedMonteCarloRewards(t *testing.T) {
+	rand.Seed(1)
+	res, err := NewNestedMonteCarlo(NestedConfig{}).Start(delayedState{})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"later", "wait"}, res.BestSequence)
+	assert.Equal(t, 10.0, res.BestScore)
+
+	res, err = NewNestedMonteCarlo(NestedConfig{SimulationConfig: SimulationConfig{Discount: 0.05}}).Start(delayedState{})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"now"}, res.BestSequence)
+}
+
+func TestNestedMonteCarloMaxDepth(t *testing.T) {
+	rand.Seed(1)
+	// the rollouts stop after one move with an optimistic evaluation, so the best sequences
+	// end before the game does and aren't beaten later
+	simConfig := SimulationConfig{MaxDepth: 1, Evaluate: func(State) float64 { return 1 }}
+	for _, level := range []int{1, 2} {
+		res, err := NewNestedMonteCarlo(NestedConfig{Level: level, SimulationConfig: simConfig}).Start(newSumState())
+		assert.NoError(t, err)
+		assert.Equal(t, 1.0, res.BestScore)
+		assert.Len(t, res.BestSequence, level+1)
+	}
+}
+
+func TestNRPA(t *testing.T) {
+	rand.Seed(1)
+	res, err := NewNRPA(NestedConfig{Iterations: 10}).Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, []any{5, 5, 5, 5}, res.BestSequence)
+	assert.Equal(t, 0.2, res.BestScore)
+	assert.Equal(t, uint(100), res.Stats.Rollouts)
+}
+
+func TestNRPAAdapt(t *testing.T) {
+	search := newNestedSearch(NewNRPA(NestedConfig{}).config)
+	weights := search.adapt(newSumState(), map[string]float64{}, []any{5})
+	// 1 - 1/3 for the played action and -1/3 for the others
+	assert.InDelta(t, 2.0/3, weights["5"], 1e-9)
+	assert.InDelta(t, -1.0/3, weights["1"], 1e-9)
+	assert.InDelta(t, -1.0/3, weights["3"], 1e-9)
+}
+
+func TestSearchersTimeout(t *testing.T) {
+	timeout := time.Duration(0)
+	searchers := []Searcher{
+		NewNestedMonteCarlo(NestedConfig{Level: 3, MaxTimeout: &timeout}),
+		NewNRPA(NestedConfig{MaxTimeout: &timeout}),
+	}
+	for _, searcher := range searchers {
+		res, err := searcher.Start(newSumState())
+		assert.NoError(t, err)
+		assert.Equal(t, StopTimeout, res.StopReason)
+		assert.Equal(t, 0.0, res.BestScore)
+	}
+}
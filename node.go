@@ -0,0 +1,55 @@
+package mcts
+
+// Root returns the root node of the tree, nil before Start or Load.
+// Nodes must not be read while the tree is searching or pondering.
+func (mct *MonteCarloTree) Root() *Node {
+	return mct.node
+}
+
+// ID returns the ID of the node state.
+func (n *Node) ID() string {
+	return n.id
+}
+
+// Action returns the iteration that expanded the node from its parent, nil for the root.
+// Trees loaded without a codec nor root state hold the action printed as string.
+func (n *Node) Action() any {
+	return n.action
+}
+
+// State returns the node state, nil for trees loaded without a codec nor root state.
+func (n *Node) State() State {
+	return n.state
+}
+
+// Visits returns how many times the node was visited.
+func (n *Node) Visits() uint {
+	return n.nVisited
+}
+
+// Value returns the mean simulation score of the node.
+func (n *Node) Value() float64 {
+	return n.meanScore()
+}
+
+// Depth returns how deep the node is from the root the search started at.
+func (n *Node) Depth() int {
+	return n.levelY
+}
+
+// Parent returns the parent node, nil for the root.
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns the expanded children of the node.
+func (n *Node) Children() []*Node {
+	children := make([]*Node, len(n.child))
+	copy(children, n.child)
+	return children
+}
+
+// PrincipalVariation returns the expected line from this node down to a leaf.
+func (n *Node) PrincipalVariation(order PVOrder) []PVStep {
+	return principalVariation(n, order)
+}
@@ -0,0 +1,30 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNodeAccessors(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 100})
+	assert.Nil(t, tree.Root())
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	root := tree.Root()
+	assert.Nil(t, root.Parent())
+	assert.Nil(t, root.Action())
+	assert.Equal(t, uint(100), root.Visits())
+	assert.Equal(t, 0, root.Depth())
+
+	children := root.Children()
+	assert.Len(t, children, 3)
+	child := children[1]
+	assert.Equal(t, root, child.Parent())
+	assert.Equal(t, 5, child.Action())
+	assert.Equal(t, "1-5", child.ID())
+	assert.Equal(t, child.State().ID(), child.ID())
+	assert.Equal(t, child.score/float64(child.nVisited), child.Value())
+	assert.Equal(t, 1, child.Depth())
+	assert.Equal(t, tree.PrincipalVariation(MostVisited), root.PrincipalVariation(MostVisited))
+}
@@ -0,0 +1,104 @@
+package mcts
+
+import (
+	"fmt"
+	"time"
+)
+
+// iterateOpenLoop runs one iteration of the open loop search: nodes below the root hold only
+// action statistics, and the state is expanded again from the root on every descent, so the
+// stochastic outcomes of Expand are sampled instead of frozen at expansion time.
+// The actions of a node are the ones of its first visit, actions not available in the
+// sampled state are skipped.
+func (mct *MonteCarloTree) iterateOpenLoop() (bool, error) {
+	lap := time.Now()
+	state := mct.node.state.Copy()
+	n := mct.node
+	for {
+		actions := state.Iterations()
+		if n.iterations == nil {
+			if actions == nil {
+				return false, fmt.Errorf("iterations return nil")
+			}
+			n.iterations = actions
+		}
+		legal := make(map[string]bool, len(actions))
+		for _, action := range actions {
+			legal[actionLabel(action)] = true
+		}
+
+		if actionIdx, ok := n.nextLegalIteration(legal); ok && !mct.budget.limitReached() {
+			mct.timings.selection += time.Since(lap)
+			lap = time.Now()
+			child, next, err := n.expandOpenLoop(state, actionIdx)
+			mct.timings.expansion += time.Since(lap)
+			if err != nil {
+				return false, err
+			}
+			mct.budget.add(child)
+			mct.trace(child, true, mct.rollOutFrom(child, next))
+			return true, nil
+		}
+
+		var selected *Node
+		for _, scored := range getNodeScore(n, mct.selector()) {
+			if legal[actionLabel(scored.node.action)] {
+				selected = scored.node
+				break
+			}
+		}
+		if selected == nil {
+			mct.timings.selection += time.Since(lap)
+			mct.trace(n, false, mct.rollOutFrom(n, state))
+			return false, nil
+		}
+		next, reward := transition(state, selected.action)
+		if next == nil {
+			return false, fmt.Errorf("expand return nil")
+		}
+		// the transition reward is averaged over the sampled outcomes
+		selected.reward += (reward - selected.reward) / float64(selected.nVisited+1)
+		state = next
+		n = selected
+	}
+}
+
+// nextLegalIteration returns the index of the first untried iteration available in legal,
+// reporting whether there is one. Iterations keep their order, since the children refer to
+// them by index once the tree is saved.
+func (n *Node) nextLegalIteration(legal map[string]bool) (int, bool) {
+	if n.currIterationIdx >= len(n.iterations) {
+		return 0, false
+	}
+	tried := make([]bool, len(n.iterations))
+	for _, child := range n.child {
+		tried[child.actionIdx] = true
+	}
+	for idx, iter := range n.iterations {
+		if !tried[idx] && legal[actionLabel(iter)] {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// expandOpenLoop adds a child for the iteration at actionIdx played from state, the child
+// doesn't keep the expanded state, which is returned to be rolled out.
+func (n *Node) expandOpenLoop(state State, actionIdx int) (*Node, State, error) {
+	action := n.iterations[actionIdx]
+	next, reward := transition(state, action)
+	if next == nil {
+		return nil, nil, fmt.Errorf("expand return nil")
+	}
+	n.currIterationIdx++
+	child := &Node{
+		id:        next.ID(),
+		parent:    n,
+		levelY:    n.levelY + 1,
+		action:    action,
+		actionIdx: actionIdx,
+		reward:    reward,
+	}
+	n.child = append(n.child, child)
+	return child, next, nil
+}
@@ -0,0 +1,207 @@
+package mcts
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// gambleState scores 0.5 on "safe", while "gamble" wins 1 with probability 0.3, drawn on Expand.
+type gambleState struct {
+	played string
+	won    bool
+}
+
+func (g gambleState) Simulate() float64 {
+	switch {
+	case g.played == "safe":
+		return 0.5
+	case g.won:
+		return 1
+	}
+	return 0
+}
+
+func (g gambleState) Expand(iter any) State {
+	return gambleState{played: iter.(string), won: iter == "gamble" && rand.Float64() < 0.3}
+}
+
+func (g gambleState) Iterations() []any {
+	if g.played != "" {
+		return []any{}
+	}
+	return []any{"gamble", "safe"}
+}
+
+func (g gambleState) Copy() State {
+	return g
+}
+
+func (g gambleState) ID() string {
+	return g.played
+}
+
+func TestOpenLoop(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 1000, OpenLoop: true})
+	res, err := tree.Start(gambleState{})
+	assert.NoError(t, err)
+	assert.Equal(t, "safe", res.NodeScore[0].Action)
+	assert.Equal(t, "safe", res.NodeScore[0].State.(gambleState).played)
+	assert.Equal(t, uint(2), res.TotalNodes)
+
+	for _, child := range tree.node.child {
+		assert.Nil(t, child.state)
+		if child.action == "gamble" {
+			assert.InDelta(t, 0.3, child.meanScore(), 0.15)
+		}
+	}
+}
+
+func TestOpenLoopDeepTree(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 300, OpenLoop: true, MaxNodes: 50})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+	assert.Equal(t, uint(50), tree.budget.nodes)
+	assert.Equal(t, uint(300), tree.node.nVisited)
+}
+
+// luckState draws a random luck on every move, so its outcomes rarely share an ID.
+type luckState struct {
+	depth, luck int
+}
+
+func (l luckState) Simulate() float64 {
+	return float64(l.luck) / 200
+}
+
+func (l luckState) Expand(iter any) State {
+	l.depth++
+	l.luck += rand.Intn(100)
+	if iter == "b" {
+		l.luck += 10
+	}
+	return l
+}
+
+func (l luckState) Iterations() []any {
+	if l.depth >= 2 {
+		return []any{}
+	}
+	return []any{"a", "b"}
+}
+
+func (l luckState) Copy() State {
+	return l
+}
+
+func (l luckState) ID() string {
+	return fmt.Sprintf("%d-%d", l.depth, l.luck)
+}
+
+func TestOpenLoopAdvance(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 200, OpenLoop: true})
+	res, err := tree.Start(luckState{})
+	assert.NoError(t, err)
+
+	// the root children keep no state, the new root searches from the one given
+	assert.NoError(t, tree.Advance(res.NodeScore[0].State))
+	_, err = tree.Continue()
+	assert.NoError(t, err)
+
+	tree = NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 200, OpenLoop: true})
+	_, err = tree.Start(luckState{})
+	assert.NoError(t, err)
+	visited := tree.node.child[0].nVisited
+	action := tree.node.child[0].action
+	reached := luckState{}.Expand(action).(luckState)
+	reached.luck += 1000
+
+	// an outcome that was never sampled still finds its child by action
+	assert.NoError(t, tree.AdvanceAction(action, reached))
+	assert.Equal(t, visited, tree.node.nVisited)
+	assert.Equal(t, reached, tree.node.state)
+	res, err = tree.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, visited+200, tree.node.nVisited)
+	assert.Greater(t, res.NodeScore[0].State.(luckState).luck, 1000)
+}
+
+func TestNextLegalIteration(t *testing.T) {
+	n := &Node{iterations: []any{1, 2, 3}, currIterationIdx: 1}
+	n.child = []*Node{{actionIdx: 0}}
+	idx, ok := n.nextLegalIteration(map[string]bool{"1": true, "3": true})
+	assert.True(t, ok)
+	assert.Equal(t, 2, idx)
+	assert.Equal(t, []any{1, 2, 3}, n.iterations)
+	_, ok = n.nextLegalIteration(map[string]bool{"1": true})
+	assert.False(t, ok)
+}
+
+// shrinkState offers "a", "b" and "c" after its first move, "b" only while hideB is false.
+type shrinkState struct {
+	path  string
+	hideB *bool
+}
+
+func (s shrinkState) Simulate() float64 {
+	return float64(len(s.path))
+}
+
+func (s shrinkState) Expand(iter any) State {
+	s.path += iter.(string)
+	return s
+}
+
+func (s shrinkState) Iterations() []any {
+	switch {
+	case s.path == "":
+		return []any{"x"}
+	case len(s.path) > 1:
+		return []any{}
+	case *s.hideB:
+		return []any{"a", "c"}
+	}
+	return []any{"a", "b", "c"}
+}
+
+func (s shrinkState) Copy() State {
+	return s
+}
+
+func (s shrinkState) ID() string {
+	return s.path
+}
+
+func TestOpenLoopSaveLoad(t *testing.T) {
+	hideB := false
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 2, OpenLoop: true})
+	_, err := tree.Start(shrinkState{hideB: &hideB})
+	assert.NoError(t, err)
+
+	// "c" is tried while "b" is not available
+	hideB = true
+	_, err = tree.Continue()
+	assert.NoError(t, err)
+	x := tree.node.child[0]
+	assert.Equal(t, []any{"a", "b", "c"}, x.iterations)
+	assert.Equal(t, []any{"a", "c"}, []any{x.child[0].action, x.child[1].action})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, nil))
+	hideB = false
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 10, OpenLoop: true})
+	assert.NoError(t, loaded.Load(buf, shrinkState{hideB: &hideB}, nil))
+	assertSameTree(t, tree.node, loaded.node)
+	loadedX := loaded.node.child[0]
+	assert.Equal(t, []any{"a", "c"}, []any{loadedX.child[0].action, loadedX.child[1].action})
+
+	_, err = loaded.Continue()
+	assert.NoError(t, err)
+	assert.Len(t, loadedX.child, 3)
+}
@@ -0,0 +1,355 @@
+package mcts
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LockStrategy is how the workers of a parallel search share the tree.
+type LockStrategy string
+
+const (
+	// GlobalLock serializes selection, expansion and backpropagation, only rollouts run in parallel.
+	GlobalLock LockStrategy = "global"
+	// NodeLock locks each node while its children are selected, expanded or its statistics updated.
+	NodeLock LockStrategy = "node"
+	// LockFree updates and reads the statistics with atomic operations, nodes are only locked
+	// to read or add their children.
+	LockFree LockStrategy = "lockfree"
+)
+
+// VirtualLossMode is how pending rollouts change the statistics seen by the other workers.
+type VirtualLossMode string
+
+const (
+	// ConstantVirtualLoss takes Amount from the score of a node for each worker below it.
+	ConstantVirtualLoss VirtualLossMode = "constant"
+	// ScaledVirtualLoss counts each worker below a node as a visit scoring -Amount,
+	// so it weighs less on nodes with more visits.
+	ScaledVirtualLoss VirtualLossMode = "scaled"
+	// NoVirtualLoss lets the workers select the same path.
+	NoVirtualLoss VirtualLossMode = "none"
+)
+
+// VirtualLoss discourages the workers of a parallel search from selecting the same path.
+type VirtualLoss struct {
+	// Mode is ConstantVirtualLoss by default.
+	Mode VirtualLossMode
+	// Amount is 1 by default.
+	Amount float64
+	// Win adds Amount instead, drawing the workers to the same path.
+	Win bool
+}
+
+// ParallelConfig searches a shared tree with several workers. The states are read by many workers
+// at once, so Copy, and the methods CopyPolicy declares read only, must be safe for concurrent use,
+// as well as Metrics. RAVE, MDP, SinglePlayer, OpenLoop, MaxNodes, MaxMemory, Progress,
+// EarlyStop and Trace aren't supported.
+type ParallelConfig struct {
+	// Workers is how many goroutines search the tree, one or less searches sequentially.
+	Workers int
+	// Lock is GlobalLock by default.
+	Lock        LockStrategy
+	VirtualLoss VirtualLoss
+}
+
+// parallelNode holds the statistics of a node shared by the workers.
+type parallelNode struct {
+	mu sync.Mutex
+	// visits and score are added by LockFree workers, they're folded into the node after the search.
+	visits atomic.Uint64
+	score  atomic.Uint64
+	// pending is how many workers are rolling out below the node.
+	pending atomic.Int64
+}
+
+type parallelSearch struct {
+	mct    *MonteCarloTree
+	config ParallelConfig
+	global sync.Mutex
+}
+
+func (mct *MonteCarloTree) checkParallel() error {
+	unsupported := []struct {
+		name string
+		set  bool
+	}{
+		{"RAVE", mct.rave.enabled()},
+		{"MDP", mct.mdp.Enabled},
+		{"SinglePlayer", mct.singlePlayer.Enabled},
+		{"OpenLoop", mct.openLoop},
+		{"MaxNodes", mct.budget.maxNodes > 0},
+		{"MaxMemory", mct.budget.maxMemory > 0},
+		{"Progress", mct.progress.OnProgress != nil},
+		{"EarlyStop", mct.earlyStop.Unreachable || mct.earlyStop.StableIterations > 0},
+		{"Trace", mct.tracer.config.Logger != nil},
+	}
+	for _, feature := range unsupported {
+		if feature.set {
+			return fmt.Errorf("parallel search doesn't support %s", feature.name)
+		}
+	}
+	switch mct.parallel.Lock {
+	case "", GlobalLock, NodeLock, LockFree:
+	default:
+		return fmt.Errorf("unknown lock strategy %q", mct.parallel.Lock)
+	}
+	return nil
+}
+
+// startParallel runs the search with ParallelConfig.Workers goroutines until a limit is reached.
+func (mct *MonteCarloTree) startParallel() (FinalScore, error) {
+	if err := mct.checkParallel(); err != nil {
+		return FinalScore{}, err
+	}
+	p := &parallelSearch{mct: mct, config: mct.parallel}
+	if p.config.Lock == "" {
+		p.config.Lock = GlobalLock
+	}
+	if p.config.VirtualLoss.Amount == 0 {
+		p.config.VirtualLoss.Amount = 1
+	}
+	walk(mct.node, func(n *Node) {
+		n.par = &parallelNode{}
+	})
+
+	started := time.Now()
+	var iterations, nodes atomic.Uint64
+	var stop, timedOut atomic.Bool
+	var errOnce sync.Once
+	var searchErr error
+	timings := make([]searchTimings, p.config.Workers)
+	wg := sync.WaitGroup{}
+	for w := 0; w < p.config.Workers; w++ {
+		wg.Add(1)
+		go func(timing *searchTimings) {
+			defer wg.Done()
+			for !stop.Load() && p.claim(&iterations) {
+				expanded, err := p.iterate(timing)
+				if err != nil {
+					errOnce.Do(func() { searchErr = err })
+					stop.Store(true)
+					return
+				}
+				if expanded {
+					nodes.Add(1)
+				}
+				if mct.maxTimeout != nil && time.Since(started) >= *mct.maxTimeout {
+					timedOut.Store(true)
+					stop.Store(true)
+				}
+			}
+		}(&timings[w])
+	}
+	wg.Wait()
+
+	walk(mct.node, func(n *Node) {
+		n.nVisited += uint(n.par.visits.Load())
+		n.score += math.Float64frombits(n.par.score.Load())
+		n.par = nil
+	})
+	mct.budget.recount(mct.node)
+	if searchErr != nil {
+		return FinalScore{}, searchErr
+	}
+
+	mct.timings = searchTimings{}
+	for _, timing := range timings {
+		mct.timings.selection += timing.selection
+		mct.timings.expansion += timing.expansion
+		mct.timings.simulation += timing.simulation
+		mct.timings.backPropagation += timing.backPropagation
+		mct.timings.rollouts += timing.rollouts
+	}
+	stopReason := StopMaxIterations
+	if timedOut.Load() {
+		stopReason = StopTimeout
+	}
+	return mct.finalScore(uint(iterations.Load()), uint(nodes.Load()), stopReason, time.Since(started)), nil
+}
+
+// claim reserves one iteration, reporting false once MaxIterations were claimed.
+func (p *parallelSearch) claim(iterations *atomic.Uint64) bool {
+	for {
+		claimed := iterations.Load()
+		if p.mct.maxInteractions > 0 && claimed >= uint64(p.mct.maxInteractions) {
+			return false
+		}
+		if iterations.CompareAndSwap(claimed, claimed+1) {
+			return true
+		}
+	}
+}
+
+// iterate runs one iteration of a worker, returning whether a node was added to the tree.
+func (p *parallelSearch) iterate(timing *searchTimings) (bool, error) {
+	lap := time.Now()
+	path, expanded, err := p.descend()
+	timing.selection += time.Since(lap)
+	if err != nil {
+		return false, err
+	}
+
+	lap = time.Now()
+	score, _ := recordedSimulation(path[len(path)-1].state, p.mct.simulationsConfig, false)
+	elapsed := time.Since(lap)
+	timing.simulation += elapsed
+	if p.mct.metrics != nil {
+		p.mct.metrics.ObserveRollout(elapsed)
+	}
+
+	lap = time.Now()
+	p.backPropagate(path, score)
+	timing.backPropagation += time.Since(lap)
+	timing.rollouts++
+	return expanded, nil
+}
+
+// descend selects a path from the root to the node to roll out, expanding it when possible.
+// The virtual loss is added to every node of the path but the root.
+func (p *parallelSearch) descend() ([]*Node, bool, error) {
+	if p.config.Lock == GlobalLock {
+		p.global.Lock()
+		defer p.global.Unlock()
+	}
+	path := []*Node{p.mct.node}
+	n := p.mct.node
+	for {
+		p.lock(n)
+		if n.iterations == nil || n.currIterationIdx < len(n.iterations) {
+			child, err := n.expand()
+			if err != nil {
+				p.unlock(n)
+				return nil, false, err
+			}
+			if child == n {
+				p.unlock(n)
+				return path, false, nil
+			}
+			child.par = &parallelNode{}
+			child.par.pending.Add(1)
+			p.unlock(n)
+			return append(path, child), true, nil
+		}
+		child := p.selectChild(n)
+		if child != nil {
+			child.par.pending.Add(1)
+		}
+		p.unlock(n)
+		if child == nil {
+			return path, false, nil
+		}
+		path = append(path, child)
+		n = child
+	}
+}
+
+// selectChild returns the child of n with the best policy score counting the virtual loss,
+// children that weren't rolled out yet are skipped. The caller holds the lock of n.
+func (p *parallelSearch) selectChild(n *Node) *Node {
+	_, parentVisits := p.stats(n, true)
+	var best *Node
+	bestScore, bestVisits := math.Inf(-1), uint(0)
+	for _, child := range n.child {
+		total, visits := p.stats(child, false)
+		if visits == 0 {
+			continue
+		}
+		score := p.mct.policy(total, visits, parentVisits)
+		if best == nil || score > bestScore || (score == bestScore && visits < bestVisits) {
+			best, bestScore, bestVisits = child, score, visits
+		}
+	}
+	return best
+}
+
+// stats returns the statistics of n with its virtual loss, locked tells whether the caller holds the lock of n.
+func (p *parallelSearch) stats(n *Node, locked bool) (float64, uint) {
+	var total float64
+	var visits uint
+	switch p.config.Lock {
+	case NodeLock:
+		if !locked {
+			n.par.mu.Lock()
+		}
+		total, visits = n.score, n.nVisited
+		if !locked {
+			n.par.mu.Unlock()
+		}
+	case LockFree:
+		total = n.score + math.Float64frombits(n.par.score.Load())
+		visits = n.nVisited + uint(n.par.visits.Load())
+	default:
+		total, visits = n.score, n.nVisited
+	}
+
+	pending := n.par.pending.Load()
+	virtual := p.config.VirtualLoss
+	if pending == 0 || virtual.Mode == NoVirtualLoss {
+		return total, visits
+	}
+	loss := virtual.Amount * float64(pending)
+	if virtual.Win {
+		loss = -loss
+	}
+	total -= loss
+	if virtual.Mode == ScaledVirtualLoss {
+		visits += uint(pending)
+	}
+	return total, visits
+}
+
+// backPropagate adds score to the nodes of path and removes their virtual loss.
+func (p *parallelSearch) backPropagate(path []*Node, score float64) {
+	if p.config.Lock == GlobalLock {
+		p.global.Lock()
+		defer p.global.Unlock()
+	}
+	for idx, n := range path {
+		switch p.config.Lock {
+		case LockFree:
+			n.par.visits.Add(1)
+			addFloat(&n.par.score, score)
+		default:
+			p.lock(n)
+			n.nVisited++
+			n.score += score
+			p.unlock(n)
+		}
+		if idx > 0 {
+			n.par.pending.Add(-1)
+		}
+	}
+}
+
+// lock locks n unless the tree is locked as a whole.
+func (p *parallelSearch) lock(n *Node) {
+	if p.config.Lock != GlobalLock {
+		n.par.mu.Lock()
+	}
+}
+
+func (p *parallelSearch) unlock(n *Node) {
+	if p.config.Lock != GlobalLock {
+		n.par.mu.Unlock()
+	}
+}
+
+func addFloat(value *atomic.Uint64, delta float64) {
+	for {
+		old := value.Load()
+		if value.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+func walk(n *Node, visit func(n *Node)) {
+	visit(n)
+	for _, child := range n.child {
+		walk(child, visit)
+	}
+}
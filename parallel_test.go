@@ -0,0 +1,151 @@
+package mcts
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestParallelSearch(t *testing.T) {
+	for _, lock := range []LockStrategy{GlobalLock, NodeLock, LockFree} {
+		for _, mode := range []VirtualLossMode{ConstantVirtualLoss, ScaledVirtualLoss, NoVirtualLoss} {
+			tree := NewMonteCarloTree(MonteCarloTreeConfig{
+				MaxIterations: 400,
+				Parallel:      ParallelConfig{Workers: 4, Lock: lock, VirtualLoss: VirtualLoss{Mode: mode}},
+			})
+			res, err := tree.Start(newSumState())
+			name := fmt.Sprintf("%s %s", lock, mode)
+			assert.NoError(t, err, name)
+			assert.Equal(t, uint(400), res.Iterations, name)
+			assert.Equal(t, uint(400), tree.node.nVisited, name)
+			assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total, name)
+			assert.Equal(t, res.TotalNodes+1, res.Stats.TotalNodes, name)
+			assert.Equal(t, uint(400), res.Stats.Rollouts, name)
+
+			walk(tree.node, func(n *Node) {
+				assert.Nil(t, n.par)
+			})
+		}
+	}
+}
+
+func TestParallelContinue(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxTimeout: &timeout,
+		Parallel:   ParallelConfig{Workers: 2, Lock: LockFree},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, StopTimeout, res.StopReason)
+
+	visits := tree.node.nVisited
+	res, err = tree.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, visits+res.Stats.Iterations, tree.node.nVisited)
+}
+
+func TestParallelUnsupported(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		Parallel: ParallelConfig{Workers: 2},
+		RAVE:     RAVEConfig{Equivalence: 10},
+	})
+	_, err := tree.Start(newSumState())
+	assert.EqualError(t, err, "parallel search doesn't support RAVE")
+
+	tree = NewMonteCarloTree(MonteCarloTreeConfig{
+		Parallel: ParallelConfig{Workers: 2},
+		Trace:    TraceConfig{Logger: slog.Default()},
+	})
+	_, err = tree.Start(newSumState())
+	assert.EqualError(t, err, "parallel search doesn't support Trace")
+
+	tree = NewMonteCarloTree(MonteCarloTreeConfig{Parallel: ParallelConfig{Workers: 2, Lock: "spin"}})
+	_, err = tree.Start(newSumState())
+	assert.EqualError(t, err, `unknown lock strategy "spin"`)
+}
+
+func TestVirtualLoss(t *testing.T) {
+	n := &Node{score: 3, nVisited: 4, par: &parallelNode{}}
+	n.par.pending.Add(2)
+	stats := func(virtual VirtualLoss) (float64, uint) {
+		p := parallelSearch{config: ParallelConfig{Lock: GlobalLock, VirtualLoss: virtual}}
+		return p.stats(n, true)
+	}
+
+	total, visits := stats(VirtualLoss{Amount: 1})
+	assert.Equal(t, 1.0, total)
+	assert.Equal(t, uint(4), visits)
+
+	total, visits = stats(VirtualLoss{Mode: ScaledVirtualLoss, Amount: 1})
+	assert.Equal(t, 1.0, total)
+	assert.Equal(t, uint(6), visits)
+
+	total, visits = stats(VirtualLoss{Amount: 0.5, Win: true})
+	assert.Equal(t, 4.0, total)
+	assert.Equal(t, uint(4), visits)
+
+	total, visits = stats(VirtualLoss{Mode: NoVirtualLoss, Amount: 1})
+	assert.Equal(t, 3.0, total)
+	assert.Equal(t, uint(4), visits)
+}
+
+// slowState spends some work on every simulation, like the rollouts of a real game.
+type slowState struct {
+	sumState
+}
+
+func (s slowState) Simulate() float64 {
+	work := 0
+	for i := 0; i < 20000; i++ {
+		work += i % 7
+	}
+	workSink = work
+	return s.sumState.Simulate()
+}
+
+var workSink int
+
+func (s slowState) Expand(iter any) State {
+	return slowState{sumState: s.sumState.Expand(iter).(sumState)}
+}
+
+func (s slowState) Copy() State {
+	return s
+}
+
+func benchmarkSearch(b *testing.B, parallel ParallelConfig) {
+	iterations := uint(0)
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 2000, Parallel: parallel})
+		res, err := tree.Start(slowState{sumState: newSumState()})
+		if err != nil {
+			b.Fatal(err)
+		}
+		iterations += res.Stats.Iterations
+	}
+	b.ReportMetric(float64(iterations)/time.Since(start).Seconds(), "iterations/s")
+}
+
+func BenchmarkSearchSequential(b *testing.B) {
+	benchmarkSearch(b, ParallelConfig{})
+}
+
+func BenchmarkSearchGlobalLock(b *testing.B) {
+	benchmarkSearch(b, ParallelConfig{Workers: 4, Lock: GlobalLock})
+}
+
+func BenchmarkSearchNodeLock(b *testing.B) {
+	benchmarkSearch(b, ParallelConfig{Workers: 4, Lock: NodeLock})
+}
+
+func BenchmarkSearchLockFree(b *testing.B) {
+	benchmarkSearch(b, ParallelConfig{Workers: 4, Lock: LockFree})
+}
+
+func BenchmarkSearchScaledVirtualLoss(b *testing.B) {
+	benchmarkSearch(b, ParallelConfig{Workers: 4, Lock: LockFree, VirtualLoss: VirtualLoss{Mode: ScaledVirtualLoss}})
+}
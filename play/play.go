@@ -0,0 +1,143 @@
+// Package play drives turn based games between players until the end.
+package play
+
+import (
+	"fmt"
+
+	"github.com/danielsussa/mcts"
+)
+
+// Draw is the winner of a game nobody won, or of a game without winner.
+const Draw = -1
+
+// Game is a turn based game played by one or more seats, it's over once it has no Iterations.
+// Games can implement TurnGame, WinnerGame, ChanceGame and SeatGame to tell more about themselves.
+type Game interface {
+	mcts.State
+}
+
+// TurnGame is a Game with several seats, the others are played by seat 0 alone.
+type TurnGame interface {
+	Game
+	// Turn returns the seat of the player to move.
+	Turn() int
+}
+
+// WinnerGame is a Game with a winner, which may end before it runs out of Iterations.
+type WinnerGame interface {
+	Game
+	// Winner returns the winner seat or Draw, and whether the game is over.
+	Winner() (int, bool)
+}
+
+// ChanceGame is a Game where random events follow every move, like the new tile of 2048.
+// Run plays them after each move, so the players search the moves only.
+type ChanceGame interface {
+	Game
+	// Chance plays the random events following a move, the game may be modified.
+	Chance() Game
+}
+
+// SeatGame is a Game that scores its simulations for any seat. Other games score them for seat 0:
+// positive when seat 0 wins and negative when another seat wins, as in two player zero-sum games.
+type SeatGame interface {
+	Game
+	// SimulateSeat plays a random game from the state and scores it for seat.
+	SimulateSeat(seat int) float64
+}
+
+// Move is one entry of the move log.
+type Move struct {
+	Turn   int
+	Seat   int
+	Player string
+	Action any
+	// Before and After are the game before and after the move, and its chance events.
+	Before Game
+	After  Game
+}
+
+// Hooks are called around every turn, all of them are optional.
+type Hooks struct {
+	// BeforeTurn is called before the player chooses its move, returning false ends the game.
+	BeforeTurn func(turn int, g Game) bool
+	// AfterTurn is called once the move is applied.
+	AfterTurn func(m Move)
+}
+
+// Options of Run.
+type Options struct {
+	// MaxTurns ends the game after MaxTurns moves, zero means no limit.
+	MaxTurns int
+	Hooks    Hooks
+}
+
+// Result is the end of a game.
+type Result struct {
+	// Winner is the winner seat or Draw.
+	Winner int
+	// Over is false when the game was interrupted by MaxTurns or a hook.
+	Over  bool
+	Moves []Move
+	Final Game
+}
+
+// Run plays g until it's over, players are indexed by seat.
+func Run(g Game, players []Player, opts Options) (Result, error) {
+	moves := make([]Move, 0)
+	for turn := 0; opts.MaxTurns == 0 || turn < opts.MaxTurns; turn++ {
+		if winner, over := outcome(g); over {
+			return Result{Winner: winner, Over: true, Moves: moves, Final: g}, nil
+		}
+		if opts.Hooks.BeforeTurn != nil && !opts.Hooks.BeforeTurn(turn, g) {
+			break
+		}
+
+		seat := 0
+		if turnGame, ok := g.(TurnGame); ok {
+			seat = turnGame.Turn()
+		}
+		if seat < 0 || seat >= len(players) {
+			return Result{}, fmt.Errorf("no player for seat %d", seat)
+		}
+		player := players[seat]
+		action, err := player.Choose(g, seat)
+		if err != nil {
+			return Result{}, fmt.Errorf("%s: %w", player.Name(), err)
+		}
+		next := g.Copy().Expand(action)
+		if next == nil {
+			return Result{}, fmt.Errorf("%s: invalid move %v", player.Name(), action)
+		}
+		if chance, ok := next.(ChanceGame); ok {
+			next = chance.Chance()
+		}
+
+		move := Move{
+			Turn:   turn,
+			Seat:   seat,
+			Player: player.Name(),
+			Action: action,
+			Before: g,
+			After:  next,
+		}
+		moves = append(moves, move)
+		if opts.Hooks.AfterTurn != nil {
+			opts.Hooks.AfterTurn(move)
+		}
+		g = next
+	}
+
+	winner, over := outcome(g)
+	return Result{Winner: winner, Over: over, Moves: moves, Final: g}, nil
+}
+
+// outcome returns the winner of g, Draw when it has none, and whether it's over.
+func outcome(g Game) (int, bool) {
+	if winnerGame, ok := g.(WinnerGame); ok {
+		if winner, over := winnerGame.Winner(); over {
+			return winner, true
+		}
+	}
+	return Draw, len(g.Iterations()) == 0
+}
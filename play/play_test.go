@@ -0,0 +1,254 @@
+package play_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/danielsussa/mcts"
+	"github.com/danielsussa/mcts/arena"
+	"github.com/danielsussa/mcts/play"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func takeAlways(n int) play.ScriptedPlayer {
+	return play.ScriptedPlayer{PlayerName: "take", Script: func(g play.Game, _ int) any {
+		if g.(arena.Nim).Stones < n {
+			return g.(arena.Nim).Stones
+		}
+		return n
+	}}
+}
+
+func TestRun(t *testing.T) {
+	afterTurns := 0
+	res, err := play.Run(arena.NewNim(7), []play.Player{takeAlways(3), takeAlways(1)}, play.Options{Hooks: play.Hooks{
+		AfterTurn: func(m play.Move) {
+			afterTurns++
+		},
+	}})
+	assert.NoError(t, err)
+
+	// 7 -> 4 -> 3 -> 0
+	assert.True(t, res.Over)
+	assert.Equal(t, 0, res.Winner)
+	assert.Len(t, res.Moves, 3)
+	assert.Equal(t, 3, afterTurns)
+	assert.Equal(t, []any{3, 1, 3}, []any{res.Moves[0].Action, res.Moves[1].Action, res.Moves[2].Action})
+	assert.Equal(t, 1, res.Moves[1].Seat)
+	assert.Equal(t, 4, res.Moves[1].Before.(arena.Nim).Stones)
+	assert.Equal(t, 3, res.Moves[1].After.(arena.Nim).Stones)
+	assert.Equal(t, 0, res.Final.(arena.Nim).Stones)
+}
+
+func TestRunMaxTurnsAndHooks(t *testing.T) {
+	res, err := play.Run(arena.NewNim(10), []play.Player{takeAlways(1), takeAlways(1)}, play.Options{MaxTurns: 4})
+	assert.NoError(t, err)
+	assert.False(t, res.Over)
+	assert.Equal(t, play.Draw, res.Winner)
+	assert.Len(t, res.Moves, 4)
+
+	res, err = play.Run(arena.NewNim(10), []play.Player{takeAlways(1), takeAlways(1)}, play.Options{Hooks: play.Hooks{
+		BeforeTurn: func(turn int, g play.Game) bool {
+			return turn < 2
+		},
+	}})
+	assert.NoError(t, err)
+	assert.False(t, res.Over)
+	assert.Len(t, res.Moves, 2)
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := play.Run(arena.NewNim(3), []play.Player{takeAlways(1)}, play.Options{})
+	assert.Error(t, err)
+
+	failing := play.HumanPlayer{PlayerName: "human", Ask: func(play.Game, int) (any, error) {
+		return nil, errors.New("left the game")
+	}}
+	_, err = play.Run(arena.NewNim(3), []play.Player{failing, failing}, play.Options{})
+	assert.EqualError(t, err, "human: left the game")
+}
+
+func TestHumanPlayerRetries(t *testing.T) {
+	answers := []any{7, "x", 2}
+	asked := 0
+	human := play.HumanPlayer{PlayerName: "human", Ask: func(play.Game, int) (any, error) {
+		asked++
+		return answers[asked-1], nil
+	}}
+	move, err := human.Choose(arena.NewNim(5), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, move)
+	assert.Equal(t, 3, asked)
+}
+
+func TestHumanPlayerErrors(t *testing.T) {
+	asked := 0
+	human := play.HumanPlayer{PlayerName: "human", Attempts: 2, Ask: func(play.Game, int) (any, error) {
+		asked++
+		return 9, nil
+	}}
+	_, err := human.Choose(arena.NewNim(5), 0)
+	assert.Error(t, err)
+	assert.Equal(t, 2, asked)
+
+	_, err = human.Choose(arena.NewNim(0), 0)
+	assert.Error(t, err)
+
+	// slices can't be compared with ==
+	path := play.HumanPlayer{PlayerName: "human", Ask: func(play.Game, int) (any, error) {
+		return []int{1, 2}, nil
+	}}
+	move, err := path.Choose(pathGame{}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, move)
+}
+
+// pathGame has uncomparable actions.
+type pathGame struct{}
+
+func (pathGame) Iterations() []any {
+	return []any{[]int{1, 1}, []int{1, 2}}
+}
+
+func (pathGame) Expand(any) mcts.State {
+	return pathGame{}
+}
+
+func (pathGame) Simulate() float64 {
+	return 0
+}
+
+func (g pathGame) Copy() mcts.State {
+	return g
+}
+
+func (pathGame) ID() string {
+	return "path"
+}
+
+// climb is a single player ChanceGame: the player climbs 1 or 2 steps and may slip back one,
+// the game is over at the top.
+type climb struct {
+	step, slips int
+}
+
+func (c climb) Iterations() []any {
+	if c.step >= 6 {
+		return []any{}
+	}
+	return []any{1, 2}
+}
+
+func (c climb) Expand(iter any) mcts.State {
+	c.step += iter.(int)
+	return c
+}
+
+func (c climb) Chance() play.Game {
+	if c.step < 6 && rand.Intn(2) == 0 {
+		c.step--
+		c.slips++
+	}
+	return c
+}
+
+func (c climb) Simulate() float64 {
+	return float64(c.step)
+}
+
+func (c climb) Copy() mcts.State {
+	return c
+}
+
+func (c climb) ID() string {
+	return fmt.Sprint(c.step)
+}
+
+func TestRunSinglePlayerChance(t *testing.T) {
+	rand.Seed(1)
+	res, err := play.Run(climb{}, []play.Player{takeTwo}, play.Options{})
+	assert.NoError(t, err)
+	assert.True(t, res.Over)
+	assert.Equal(t, play.Draw, res.Winner)
+	assert.GreaterOrEqual(t, res.Final.(climb).step, 6)
+	assert.Len(t, res.Moves, 3+res.Final.(climb).slips)
+	for _, move := range res.Moves {
+		assert.Equal(t, 0, move.Seat)
+	}
+}
+
+var takeTwo = play.ScriptedPlayer{PlayerName: "two", Script: func(play.Game, int) any { return 2 }}
+
+// tokens is a three player game, every seat scores its own tokens.
+type tokens struct {
+	turn  int
+	count [3]int
+}
+
+func (g tokens) Turn() int {
+	return g.turn
+}
+
+func (g tokens) Iterations() []any {
+	if g.count[0]+g.count[1]+g.count[2] >= 6 {
+		return []any{}
+	}
+	return []any{0, 1, 2}
+}
+
+func (g tokens) Expand(iter any) mcts.State {
+	g.count[iter.(int)]++
+	g.turn = (g.turn + 1) % 3
+	return g
+}
+
+func (g tokens) Simulate() float64 {
+	return g.SimulateSeat(0)
+}
+
+func (g tokens) SimulateSeat(seat int) float64 {
+	return float64(g.count[seat])
+}
+
+func (g tokens) Copy() mcts.State {
+	return g
+}
+
+func (g tokens) ID() string {
+	return fmt.Sprint(g.turn, g.count)
+}
+
+func TestRunSeatGame(t *testing.T) {
+	rand.Seed(1)
+	player := play.MCTSPlayer{PlayerName: "mcts", Config: mcts.MonteCarloTreeConfig{MaxIterations: 300}}
+	res, err := play.Run(tokens{}, []play.Player{player, player, player}, play.Options{})
+	assert.NoError(t, err)
+	assert.True(t, res.Over)
+	for _, move := range res.Moves {
+		// every seat gives the token to itself
+		assert.Equal(t, move.Seat, move.Action)
+	}
+}
+
+func TestMCTSPlayer(t *testing.T) {
+	rand.Seed(1)
+	player := play.MCTSPlayer{PlayerName: "mcts", Config: mcts.MonteCarloTreeConfig{MaxIterations: 500}}
+
+	// taking the last stones wins, for both seats
+	move, err := player.Choose(arena.NewNim(3), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, move)
+
+	game := arena.NewNim(4).Expand(2).(play.Game)
+	move, err = player.Choose(game, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, move)
+}
+
+func TestRandomPlayer(t *testing.T) {
+	res, err := play.Run(arena.NewNim(12), []play.Player{play.RandomPlayer{}, play.RandomPlayer{}}, play.Options{})
+	assert.NoError(t, err)
+	assert.True(t, res.Over)
+	assert.NotEqual(t, play.Draw, res.Winner)
+}
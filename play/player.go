@@ -0,0 +1,134 @@
+package play
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/danielsussa/mcts"
+)
+
+// Player chooses the moves of one seat.
+type Player interface {
+	Name() string
+	// Choose returns one of the game Iterations, seat is the index of the player to move.
+	Choose(g Game, seat int) (any, error)
+}
+
+// MCTSPlayer plays the most visited move of a new MonteCarloTree per turn.
+type MCTSPlayer struct {
+	PlayerName string
+	Config     mcts.MonteCarloTreeConfig
+}
+
+func (p MCTSPlayer) Name() string {
+	return p.PlayerName
+}
+
+func (p MCTSPlayer) Choose(g Game, seat int) (any, error) {
+	tree := mcts.NewMonteCarloTree(p.Config)
+	res, err := tree.Start(seatState{Game: g, seat: seat})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.NodeScore) == 0 {
+		return nil, fmt.Errorf("no move found")
+	}
+	return res.NodeScore[0].Action, nil
+}
+
+// seatState scores simulations for the seat the player plays, with SimulateSeat when the game
+// implements SeatGame, or negating the seat 0 score for the other seats.
+type seatState struct {
+	Game
+	seat int
+}
+
+func (s seatState) Simulate() float64 {
+	if seatGame, ok := s.Game.(SeatGame); ok {
+		return seatGame.SimulateSeat(s.seat)
+	}
+	score := s.Game.Simulate()
+	if s.seat != 0 {
+		return -score
+	}
+	return score
+}
+
+func (s seatState) Expand(iter any) mcts.State {
+	return seatState{Game: s.Game.Expand(iter), seat: s.seat}
+}
+
+func (s seatState) Copy() mcts.State {
+	return seatState{Game: s.Game.Copy(), seat: s.seat}
+}
+
+// RandomPlayer plays uniformly random moves.
+type RandomPlayer struct{}
+
+func (RandomPlayer) Name() string {
+	return "random"
+}
+
+func (RandomPlayer) Choose(g Game, _ int) (any, error) {
+	iterations := g.Iterations()
+	if len(iterations) == 0 {
+		return nil, fmt.Errorf("no move found")
+	}
+	return iterations[rand.Intn(len(iterations))], nil
+}
+
+// ScriptedPlayer plays the move returned by Script.
+type ScriptedPlayer struct {
+	PlayerName string
+	Script     func(g Game, seat int) any
+}
+
+func (p ScriptedPlayer) Name() string {
+	return p.PlayerName
+}
+
+func (p ScriptedPlayer) Choose(g Game, seat int) (any, error) {
+	move := p.Script(g, seat)
+	if move == nil {
+		return nil, fmt.Errorf("no move found")
+	}
+	return move, nil
+}
+
+// HumanPlayer asks the move to Ask, usually reading it from a terminal or UI.
+// Ask is called again while it returns a move that isn't one of the game Iterations.
+type HumanPlayer struct {
+	PlayerName string
+	Ask        func(g Game, seat int) (any, error)
+	// Attempts is how many times Ask is called before Choose fails, 3 by default.
+	Attempts int
+}
+
+func (p HumanPlayer) Name() string {
+	return p.PlayerName
+}
+
+func (p HumanPlayer) Choose(g Game, seat int) (any, error) {
+	iterations := g.Iterations()
+	if len(iterations) == 0 {
+		return nil, fmt.Errorf("no move found")
+	}
+	attempts := p.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
+		move, err := p.Ask(g, seat)
+		if err != nil {
+			return nil, err
+		}
+		for _, iter := range iterations {
+			// DeepEqual since actions may not be comparable with ==
+			if reflect.DeepEqual(iter, move) {
+				return move, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no legal move after %d attempts", attempts)
+}
@@ -0,0 +1,136 @@
+package mcts
+
+import (
+	"fmt"
+)
+
+type pondering struct {
+	stop       chan struct{}
+	done       chan struct{}
+	iterations uint
+	err        error
+}
+
+// Continue searches again from the current root, keeping the statistics of previous searches.
+// Pondering is stopped before the search starts.
+func (mct *MonteCarloTree) Continue() (FinalScore, error) {
+	if err := mct.checkSearchable(); err != nil {
+		return FinalScore{}, err
+	}
+	if _, err := mct.StopPondering(); err != nil {
+		return FinalScore{}, err
+	}
+	return mct.start()
+}
+
+// Advance re-roots the tree to the root child with the same ID as state, keeping its statistics.
+// When no child matches, the tree restarts from state. If the tree is pondering, it keeps
+// pondering from the new root.
+func (mct *MonteCarloTree) Advance(state State) error {
+	id := state.ID()
+	return mct.advance(state, func(child *Node) bool {
+		return child.id == id
+	})
+}
+
+// AdvanceAction is Advance to the root child reached by action, state being the state it led to.
+// Open loop children keep the ID of a single sampled outcome, so they are better matched by action.
+func (mct *MonteCarloTree) AdvanceAction(action any, state State) error {
+	label := actionLabel(action)
+	return mct.advance(state, func(child *Node) bool {
+		return actionLabel(child.action) == label
+	})
+}
+
+func (mct *MonteCarloTree) advance(state State, match func(child *Node) bool) error {
+	wasPondering := mct.pondering != nil
+	if _, err := mct.StopPondering(); err != nil {
+		return err
+	}
+
+	var newRoot *Node
+	if mct.node != nil {
+		for _, child := range mct.node.child {
+			if match(child) {
+				newRoot = child
+				break
+			}
+		}
+	}
+	if newRoot == nil {
+		newRoot = &Node{id: state.ID(), state: state.Copy()}
+	}
+	if mct.openLoop {
+		// open loop children don't keep a state, the new root searches from the one reached
+		newRoot.state = state.Copy()
+	}
+	newRoot.parent = nil
+	mct.node = newRoot
+	mct.budget.recount(newRoot)
+
+	if wasPondering {
+		return mct.Ponder()
+	}
+	return nil
+}
+
+// Ponder keeps searching from the current root in a goroutine until StopPondering,
+// Advance, Continue or Start is called. Limits from the config are ignored while pondering.
+func (mct *MonteCarloTree) Ponder() error {
+	if err := mct.checkSearchable(); err != nil {
+		return err
+	}
+	if mct.pondering != nil {
+		return fmt.Errorf("tree is already pondering")
+	}
+	p := &pondering{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	mct.pondering = p
+	go func() {
+		defer close(p.done)
+		for {
+			select {
+			case <-p.stop:
+				return
+			default:
+			}
+			if _, err := mct.iterate(); err != nil {
+				p.err = err
+				return
+			}
+			p.iterations++
+		}
+	}()
+	return nil
+}
+
+// IsPondering reports whether the tree is searching in background.
+func (mct *MonteCarloTree) IsPondering() bool {
+	return mct.pondering != nil
+}
+
+// StopPondering stops the background search and waits for it to finish,
+// returning how many iterations it ran. It's a no-op when the tree isn't pondering.
+func (mct *MonteCarloTree) StopPondering() (uint, error) {
+	p := mct.pondering
+	if p == nil {
+		return 0, nil
+	}
+	close(p.stop)
+	<-p.done
+	mct.pondering = nil
+	mct.totalInteractions += p.iterations
+	return p.iterations, p.err
+}
+
+func (mct *MonteCarloTree) checkSearchable() error {
+	if mct.node == nil {
+		return fmt.Errorf("tree has no root, call Start first")
+	}
+	if mct.node.state == nil {
+		return fmt.Errorf("tree was loaded without states and cannot be searched")
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPonderAndAdvance(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 100})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	// our move
+	ourMove := res.NodeScore[0].State
+	assert.NoError(t, tree.Advance(ourMove))
+	assert.Equal(t, ourMove.ID(), tree.node.id)
+	assert.Nil(t, tree.node.parent)
+
+	assert.NoError(t, tree.Ponder())
+	assert.True(t, tree.IsPondering())
+	assert.Error(t, tree.Ponder())
+	time.Sleep(10 * time.Millisecond)
+
+	// opponent's move keeps the subtree pondered so far
+	opponentMove := ourMove.Expand(1)
+	assert.NoError(t, tree.Advance(opponentMove))
+	assert.True(t, tree.IsPondering())
+
+	iterations, err := tree.StopPondering()
+	assert.NoError(t, err)
+	assert.False(t, tree.IsPondering())
+	assert.Equal(t, opponentMove.ID(), tree.node.id)
+	assert.Greater(t, tree.node.nVisited, uint(0))
+	assert.Equal(t, countNodes(tree.node), tree.budget.nodes)
+
+	visited := tree.node.nVisited
+	res, err = tree.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, visited+100, tree.node.nVisited)
+	assert.GreaterOrEqual(t, res.Iterations, 200+iterations)
+}
+
+func TestAdvanceUnknownState(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 10})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	unknown := sumState{total: 50, depth: 1, moves: []int{1}}
+	assert.NoError(t, tree.Advance(unknown))
+	assert.Equal(t, unknown.ID(), tree.node.id)
+	assert.Equal(t, uint(0), tree.node.nVisited)
+	assert.Equal(t, uint(1), tree.budget.nodes)
+}
+
+func TestContinueWithoutStart(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{})
+	_, err := tree.Continue()
+	assert.Error(t, err)
+	assert.Error(t, tree.Ponder())
+}
@@ -0,0 +1,115 @@
+package mcts
+
+import (
+	"sort"
+	"time"
+)
+
+// ProgressFunc receives a snapshot of the search, returning false stops the search early.
+type ProgressFunc func(p Progress) bool
+
+// ProgressConfig defines when OnProgress is called during the search.
+// If neither Every nor Interval is set, OnProgress is called every 100 iterations.
+type ProgressConfig struct {
+	OnProgress ProgressFunc
+	// Every calls OnProgress each Every iterations.
+	Every uint
+	// Interval calls OnProgress when at least Interval has passed since the last call.
+	Interval time.Duration
+}
+
+// Progress is a snapshot of the root statistics taken while the search is running.
+type Progress struct {
+	Iterations          uint
+	Elapsed             time.Duration
+	IterationsPerSecond float64
+	TreeSize            uint
+	// BestMove is the most visited root child, nil before the root is expanded.
+	BestMove State
+	// Moves holds every root child sorted by visits.
+	Moves []MoveStats
+	// PV is the principal variation following the most visited children.
+	PV []PVStep
+}
+
+// MoveStats are the statistics of one root child.
+type MoveStats struct {
+	State  State
+	Visits uint
+	// Value is the mean simulation score of the move.
+	Value float64
+}
+
+type progressTracker struct {
+	config     ProgressConfig
+	startedAt  time.Time
+	lastReport time.Time
+}
+
+func newProgressTracker(config ProgressConfig) *progressTracker {
+	if config.OnProgress != nil && config.Every == 0 && config.Interval == 0 {
+		config.Every = 100
+	}
+	now := time.Now()
+	return &progressTracker{
+		config:     config,
+		startedAt:  now,
+		lastReport: now,
+	}
+}
+
+func (p *progressTracker) elapsed() time.Duration {
+	return time.Since(p.startedAt)
+}
+
+// report calls OnProgress when it's due and returns whether the search should continue.
+func (p *progressTracker) report(mct *MonteCarloTree, iterations uint) bool {
+	if p.config.OnProgress == nil {
+		return true
+	}
+	due := p.config.Every > 0 && iterations%p.config.Every == 0
+	if !due && p.config.Interval > 0 {
+		due = time.Since(p.lastReport) >= p.config.Interval
+	}
+	if !due {
+		return true
+	}
+	p.lastReport = time.Now()
+	return p.config.OnProgress(mct.snapshot(iterations, p.elapsed()))
+}
+
+func (mct *MonteCarloTree) snapshot(iterations uint, elapsed time.Duration) Progress {
+	moves := make([]MoveStats, 0, len(mct.node.child))
+	for _, child := range mct.node.child {
+		moves = append(moves, MoveStats{
+			State:  child.state,
+			Visits: child.nVisited,
+			Value:  child.meanScore(),
+		})
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].Visits > moves[j].Visits
+	})
+
+	progress := Progress{
+		Iterations: iterations,
+		Elapsed:    elapsed,
+		TreeSize:   mct.budget.nodes,
+		Moves:      moves,
+		PV:         principalVariation(mct.node, MostVisited),
+	}
+	if elapsed > 0 {
+		progress.IterationsPerSecond = float64(iterations) / elapsed.Seconds()
+	}
+	if len(moves) > 0 {
+		progress.BestMove = moves[0].State
+	}
+	return progress
+}
+
+func (n *Node) meanScore() float64 {
+	if n.nVisited == 0 {
+		return 0
+	}
+	return n.score / float64(n.nVisited)
+}
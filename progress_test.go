@@ -0,0 +1,59 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProgressEvery(t *testing.T) {
+	snapshots := make([]Progress, 0)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: 100,
+		Progress: ProgressConfig{
+			Every: 25,
+			OnProgress: func(p Progress) bool {
+				snapshots = append(snapshots, p)
+				return true
+			},
+		},
+	})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	assert.Len(t, snapshots, 3)
+	assert.Equal(t, uint(25), snapshots[0].Iterations)
+	assert.Equal(t, uint(75), snapshots[2].Iterations)
+	assert.Len(t, snapshots[2].Moves, 3)
+	assert.Equal(t, snapshots[2].Moves[0].State, snapshots[2].BestMove)
+	assert.GreaterOrEqual(t, snapshots[2].Moves[0].Visits, snapshots[2].Moves[1].Visits)
+	assert.Greater(t, snapshots[2].TreeSize, uint(3))
+}
+
+func TestProgressStop(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: 1000,
+		Progress: ProgressConfig{
+			Every: 10,
+			OnProgress: func(p Progress) bool {
+				return p.Iterations < 50
+			},
+		},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(50), res.Iterations)
+	assert.Equal(t, StopProgress, res.StopReason)
+}
+
+func TestMaxTimeout(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxTimeout: &timeout})
+
+	start := time.Now()
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), timeout)
+	assert.Greater(t, res.Iterations, uint(1))
+	assert.Equal(t, StopTimeout, res.StopReason)
+}
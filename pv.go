@@ -0,0 +1,65 @@
+package mcts
+
+// PVOrder defines which child the principal variation follows.
+type PVOrder string
+
+const (
+	MostVisited  PVOrder = "visits"
+	HighestValue PVOrder = "value"
+)
+
+// PVStep is one ply of the principal variation.
+type PVStep struct {
+	Action any
+	State  State
+	ID     string
+	Visits uint
+	// Value is the mean simulation score of the node.
+	Value float64
+}
+
+// PrincipalVariation returns the line expected by the search, from the root down to a leaf.
+func (mct *MonteCarloTree) PrincipalVariation(order PVOrder) []PVStep {
+	if mct.node == nil {
+		return nil
+	}
+	return principalVariation(mct.node, order)
+}
+
+func principalVariation(root *Node, order PVOrder) []PVStep {
+	pv := make([]PVStep, 0)
+	for n := bestChild(root, order); n != nil; n = bestChild(n, order) {
+		pv = append(pv, PVStep{
+			Action: n.action,
+			State:  n.state,
+			ID:     n.id,
+			Visits: n.nVisited,
+			Value:  n.meanScore(),
+		})
+	}
+	return pv
+}
+
+func bestChild(n *Node, order PVOrder) *Node {
+	var best *Node
+	for _, child := range n.child {
+		if child.nVisited == 0 {
+			continue
+		}
+		if best == nil {
+			best = child
+			continue
+		}
+		switch order {
+		case HighestValue:
+			if child.meanScore() > best.meanScore() {
+				best = child
+			}
+		default:
+			if child.nVisited > best.nVisited {
+				best = child
+			}
+		}
+	}
+	return best
+}
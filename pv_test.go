@@ -0,0 +1,54 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrincipalVariation(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 500})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	pv := tree.PrincipalVariation(MostVisited)
+	assert.Len(t, pv, 4)
+	for idx, step := range pv {
+		assert.Equal(t, 5, step.Action)
+		assert.Equal(t, 5*(idx+1), step.State.(sumState).total)
+		assert.Equal(t, step.State.ID(), step.ID)
+		if idx > 0 {
+			assert.LessOrEqual(t, step.Visits, pv[idx-1].Visits)
+		}
+	}
+
+	byValue := tree.PrincipalVariation(HighestValue)
+	assert.Equal(t, 5, byValue[0].Action)
+	assert.InDelta(t, 0.2, byValue[len(byValue)-1].Value, 1e-9)
+}
+
+func TestBestChild(t *testing.T) {
+	parent := &Node{}
+	c1 := &Node{score: 9, nVisited: 3}
+	c2 := &Node{score: 4, nVisited: 4}
+	c3 := &Node{score: 0, nVisited: 0}
+	parent.child = []*Node{c1, c2, c3}
+
+	assert.Equal(t, c2, bestChild(parent, MostVisited))
+	assert.Equal(t, c1, bestChild(parent, HighestValue))
+	assert.Nil(t, bestChild(c3, MostVisited))
+}
+
+func TestPrincipalVariationInProgress(t *testing.T) {
+	var last Progress
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: 300,
+		Progress: ProgressConfig{Every: 100, OnProgress: func(p Progress) bool {
+			last = p
+			return true
+		}},
+	})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, last.PV)
+	assert.Equal(t, last.BestMove, last.PV[0].State)
+}
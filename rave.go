@@ -0,0 +1,79 @@
+package mcts
+
+import "math"
+
+// RAVEConfig enables Rapid Action Value Estimation: every node keeps All Moves As First (AMAF)
+// statistics of the actions played below it, and selection blends them into the mean score
+// of the children with a weight that decays as the children are visited.
+// Rollouts must report their actions, which is the case for rollouts driven by the tree
+// and for states implementing RecordingState. Scores aren't split by player.
+type RAVEConfig struct {
+	// Equivalence is how many visits a child needs for its mean score and AMAF value
+	// to weigh the same, zero disables RAVE.
+	Equivalence float64
+}
+
+func (r RAVEConfig) enabled() bool {
+	return r.Equivalence > 0
+}
+
+// RecordingState can be implemented by a State to report the actions played by Simulate.
+type RecordingState interface {
+	// SimulateActions is Simulate returning the actions it played too.
+	SimulateActions() (float64, []any)
+}
+
+// amafTable holds the AMAF statistics of the actions played below a node.
+type amafTable struct {
+	equivalence float64
+	actions     map[string]*actionValue
+}
+
+// selectionTotal is the total score of n seen by the selection policy, the backed up value
+// times the visits with MaxBackup or MixedBackup. With RAVE it's the blend of the node mean
+// and the AMAF value of its action scaled by the visits.
+func (n *Node) selectionTotal() float64 {
+	total := n.score
+	if n.backed {
+		total = n.value * float64(n.nVisited)
+	}
+	if n.parent == nil || n.parent.amaf == nil || n.nVisited == 0 {
+		return total
+	}
+	amaf, ok := n.parent.amaf.actions[actionLabel(n.action)]
+	if !ok || amaf.visits == 0 {
+		return total
+	}
+	k := n.parent.amaf.equivalence
+	beta := math.Sqrt(k / (3*float64(n.nVisited) + k))
+	mean := total / float64(n.nVisited)
+	blended := (1-beta)*mean + beta*amaf.total/float64(amaf.visits)
+	return blended * float64(n.nVisited)
+}
+
+// updateAMAF adds score to the AMAF statistics of n and its ancestors up to root,
+// for every action played after them by the tree or the rollout.
+func (mct *MonteCarloTree) updateAMAF(n *Node, played []any, score float64) {
+	seen := make(map[string]bool)
+	for _, action := range played {
+		seen[actionLabel(action)] = true
+	}
+	for curr := n; curr != nil; curr = curr.parent {
+		if curr.amaf == nil {
+			curr.amaf = &amafTable{equivalence: mct.rave.Equivalence, actions: make(map[string]*actionValue)}
+		}
+		for key := range seen {
+			value, ok := curr.amaf.actions[key]
+			if !ok {
+				value = &actionValue{}
+				curr.amaf.actions[key] = value
+			}
+			value.total += score
+			value.visits++
+		}
+		if curr == mct.node {
+			return
+		}
+		seen[actionLabel(curr.action)] = true
+	}
+}
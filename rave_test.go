@@ -0,0 +1,69 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestUpdateAMAF(t *testing.T) {
+	root := &Node{}
+	child := &Node{parent: root, action: 1}
+	leaf := &Node{parent: child, action: 3}
+	root.child = []*Node{child}
+	child.child = []*Node{leaf}
+	tree := MonteCarloTree{node: root, rave: RAVEConfig{Equivalence: 10}}
+
+	tree.updateAMAF(leaf, []any{5, 5, 1}, 2)
+
+	value, visits := amafValue(leaf, 5)
+	assert.Equal(t, 2.0, value)
+	assert.Equal(t, uint(1), visits)
+	_, visits = amafValue(leaf, 3)
+	assert.Equal(t, uint(0), visits)
+
+	// actions played below the node count once each
+	for _, action := range []any{1, 3, 5} {
+		_, visits = amafValue(root, action)
+		assert.Equal(t, uint(1), visits)
+	}
+	_, visits = amafValue(child, 1)
+	assert.Equal(t, uint(1), visits)
+	assert.Equal(t, 10.0, root.amaf.equivalence)
+}
+
+func amafValue(n *Node, action any) (float64, uint) {
+	value, ok := n.amaf.actions[actionLabel(action)]
+	if !ok {
+		return 0, 0
+	}
+	return value.total / float64(value.visits), value.visits
+}
+
+func TestSelectionTotal(t *testing.T) {
+	root := &Node{nVisited: 4}
+	child := &Node{parent: root, action: 5, score: 0, nVisited: 1}
+	assert.Equal(t, 0.0, child.selectionTotal())
+
+	root.amaf = &amafTable{equivalence: 3, actions: map[string]*actionValue{"5": {total: 4, visits: 4}}}
+	// beta = sqrt(3 / 6)
+	assert.InDelta(t, 0.7071, child.selectionTotal(), 1e-4)
+
+	// beta decays to sqrt(3 / 3003)
+	child.nVisited = 1000
+	assert.InDelta(t, 31.607, child.selectionTotal(), 1e-3)
+}
+
+func TestTreeWithRAVE(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations:    200,
+		RAVE:             RAVEConfig{Equivalence: 50},
+		SimulationConfig: SimulationConfig{Policy: RandomPolicy{}},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+	_, visits := amafValue(tree.node, 5)
+	assert.Greater(t, visits, uint(100))
+}
@@ -0,0 +1,233 @@
+package mcts
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// TerminalState can be implemented by a State to end the rollouts driven by a RolloutPolicy
+// before it runs out of iterations.
+type TerminalState interface {
+	// Terminal returns the reward of the state and whether the game is over.
+	Terminal() (float64, bool)
+}
+
+// RolloutPolicy chooses the actions of the rollouts driven by the tree.
+type RolloutPolicy interface {
+	// Choose returns the index of the action to play from state.
+	Choose(state State, actions []any) int
+}
+
+// LearningPolicy is a RolloutPolicy that learns from the reward of every rollout.
+type LearningPolicy interface {
+	RolloutPolicy
+	// Learn receives the actions played by a rollout and its reward.
+	Learn(actions []any, reward float64)
+}
+
+// Playout plays state until it's terminal or has no iterations, choosing the actions with policy.
+// The reward is the one of TerminalState, or Simulate of the last state when it has no iterations.
+// The state may be modified, pass a copy when it's still needed.
+func Playout(state State, policy RolloutPolicy) float64 {
+	return SimulationConfig{Policy: policy}.Playout(state)
+}
+
+// Playout is the Playout of state with Policy, RandomPolicy when it's nil, stopping after MaxDepth
+// moves to score the state with Evaluate, and discounting the reward by Discount per move.
+func (s SimulationConfig) Playout(state State) float64 {
+	reward, _ := s.playout(state)
+	return reward
+}
+
+// playout is Playout returning the actions played too.
+func (s SimulationConfig) playout(state State) (float64, []any) {
+	policy := s.Policy
+	if policy == nil {
+		policy = RandomPolicy{}
+	}
+	played := make([]any, 0)
+	reward := 0.0
+	// earned sums the discounted transition rewards of RewardState
+	earned := 0.0
+	for {
+		if terminal, ok := state.(TerminalState); ok {
+			if score, over := terminal.Terminal(); over {
+				reward = score
+				break
+			}
+		}
+		if s.MaxDepth > 0 && len(played) >= s.MaxDepth {
+			reward = s.evaluate(state)
+			break
+		}
+		actions := state.Iterations()
+		if len(actions) == 0 {
+			reward = state.Simulate()
+			break
+		}
+		action := actions[policy.Choose(state, actions)]
+		next, transitionReward := transition(state, action)
+		earned += transitionReward * s.discount(len(played))
+		if next == nil {
+			reward = state.Simulate()
+			break
+		}
+		played = append(played, action)
+		state = next
+	}
+	reward = earned + reward*s.discount(len(played))
+	if learner, ok := policy.(LearningPolicy); ok {
+		learner.Learn(played, reward)
+	}
+	return reward, played
+}
+
+// discount is the factor of a reward after moves.
+func (s SimulationConfig) discount(moves int) float64 {
+	if s.Discount <= 0 || s.Discount >= 1 {
+		return 1
+	}
+	return math.Pow(s.Discount, float64(moves))
+}
+
+// evaluate scores a state cut off by MaxDepth.
+func (s SimulationConfig) evaluate(state State) float64 {
+	if s.Evaluate != nil {
+		return s.Evaluate(state)
+	}
+	return state.Simulate()
+}
+
+// playoutCopy runs a rollout of state, copying it unless no method mutates it.
+func (s SimulationConfig) playoutCopy(state State) (float64, []any) {
+	p := copyPolicyOf(state)
+	readOnly := p.IterationsReadOnly && p.ExpandReturnsFresh && p.SimulateReadOnly
+	return s.playout(stateFor(state, readOnly))
+}
+
+// RandomPolicy plays uniformly random actions.
+type RandomPolicy struct{}
+
+func (RandomPolicy) Choose(_ State, actions []any) int {
+	return rand.Intn(len(actions))
+}
+
+// EpsilonGreedyPolicy plays a random action with probability Epsilon and
+// the action with the highest Heuristic otherwise.
+type EpsilonGreedyPolicy struct {
+	Epsilon float64
+	// Heuristic scores playing action from state, higher is better.
+	Heuristic func(state State, action any) float64
+}
+
+func (e EpsilonGreedyPolicy) Choose(state State, actions []any) int {
+	if e.Heuristic == nil || rand.Float64() < e.Epsilon {
+		return rand.Intn(len(actions))
+	}
+	best, bestScore := 0, math.Inf(-1)
+	for idx, action := range actions {
+		if score := e.Heuristic(state, action); score > bestScore {
+			best, bestScore = idx, score
+		}
+	}
+	return best
+}
+
+// MASTPolicy is the Move-Average Sampling Technique: it learns the mean reward of every action
+// across rollouts and samples actions with a Gibbs distribution over those means.
+// Actions are told apart by their printed value. It's safe for concurrent use.
+type MASTPolicy struct {
+	// Temperature of the Gibbs distribution, lower values play the best actions more often.
+	Temperature float64
+	// Initial is the value of actions not played yet.
+	Initial float64
+
+	mu      sync.Mutex
+	actions map[string]*actionValue
+}
+
+type actionValue struct {
+	total  float64
+	visits uint
+}
+
+// NewMASTPolicy creates a MASTPolicy with temperature, 1 when it's not positive.
+func NewMASTPolicy(temperature float64) *MASTPolicy {
+	if temperature <= 0 {
+		temperature = 1
+	}
+	return &MASTPolicy{Temperature: temperature}
+}
+
+// Value returns the mean reward learnt for action and how many times it was played.
+func (m *MASTPolicy) Value(action any) (float64, uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.value(actionLabel(action))
+}
+
+func (m *MASTPolicy) value(key string) (float64, uint) {
+	value, ok := m.actions[key]
+	if !ok || value.visits == 0 {
+		return m.Initial, 0
+	}
+	return value.total / float64(value.visits), value.visits
+}
+
+func (m *MASTPolicy) Choose(_ State, actions []any) int {
+	m.mu.Lock()
+	values := make([]float64, len(actions))
+	max := math.Inf(-1)
+	for idx, action := range actions {
+		values[idx], _ = m.value(actionLabel(action))
+		max = math.Max(max, values[idx])
+	}
+	m.mu.Unlock()
+
+	temperature := m.Temperature
+	if temperature <= 0 {
+		temperature = 1
+	}
+	for idx, value := range values {
+		// shifted by max to avoid overflows
+		values[idx] = (value - max) / temperature
+	}
+	return gibbsIndex(values)
+}
+
+// gibbsIndex picks an index with a probability proportional to exp of its weight.
+func gibbsIndex(weights []float64) int {
+	exps := make([]float64, len(weights))
+	total := 0.0
+	for idx, weight := range weights {
+		exps[idx] = math.Exp(weight)
+		total += exps[idx]
+	}
+	pick := rand.Float64() * total
+	for idx, weight := range exps {
+		pick -= weight
+		if pick < 0 {
+			return idx
+		}
+	}
+	return len(weights) - 1
+}
+
+func (m *MASTPolicy) Learn(actions []any, reward float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.actions == nil {
+		m.actions = make(map[string]*actionValue)
+	}
+	for _, action := range actions {
+		key := actionLabel(action)
+		value, ok := m.actions[key]
+		if !ok {
+			value = &actionValue{}
+			m.actions[key] = value
+		}
+		value.total += reward
+		value.visits++
+	}
+}
@@ -0,0 +1,86 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+type terminalSumState struct {
+	sumState
+}
+
+func (s terminalSumState) Expand(iter any) State {
+	return terminalSumState{sumState: s.sumState.Expand(iter).(sumState)}
+}
+
+func (s terminalSumState) Terminal() (float64, bool) {
+	return float64(s.total), s.total >= 10
+}
+
+func TestPlayout(t *testing.T) {
+	rand.Seed(1)
+
+	// plays 4 moves and scores the last state with Simulate
+	score := Playout(newSumState(), RandomPolicy{})
+	assert.GreaterOrEqual(t, score, 0.04)
+	assert.LessOrEqual(t, score, 0.2)
+
+	// always plays 5, then the terminal reward ends the rollout
+	greedy := EpsilonGreedyPolicy{Heuristic: func(_ State, action any) float64 {
+		return float64(action.(int))
+	}}
+	assert.Equal(t, 10.0, Playout(terminalSumState{sumState: newSumState()}, greedy))
+}
+
+func TestDepthLimitedPlayout(t *testing.T) {
+	greedy := EpsilonGreedyPolicy{Heuristic: func(_ State, action any) float64 {
+		return float64(action.(int))
+	}}
+	evaluations := 0
+	config := SimulationConfig{Policy: greedy, MaxDepth: 1, Evaluate: func(state State) float64 {
+		evaluations++
+		return float64(state.(sumState).total)
+	}}
+	assert.Equal(t, 5.0, config.Playout(newSumState()))
+	assert.Equal(t, 1, evaluations)
+
+	// reaching the end before the cutoff doesn't evaluate
+	config.MaxDepth = 10
+	assert.Equal(t, 0.2, config.Playout(newSumState()))
+	assert.Equal(t, 1, evaluations)
+
+	// two moves until the terminal reward of 10
+	discounted := SimulationConfig{Policy: greedy, Discount: 0.5}
+	assert.Equal(t, 2.5, discounted.Playout(terminalSumState{sumState: newSumState()}))
+	assert.True(t, discounted.drivesRollouts())
+	assert.False(t, SimulationConfig{Discount: 1}.drivesRollouts())
+}
+
+func TestMASTPolicy(t *testing.T) {
+	rand.Seed(1)
+	mast := NewMASTPolicy(0.01)
+
+	for i := 0; i < 200; i++ {
+		Playout(newSumState(), mast)
+	}
+	five, visits := mast.Value(5)
+	one, _ := mast.Value(1)
+	assert.Greater(t, five, one)
+	assert.Greater(t, visits, uint(400))
+
+	unknown, visits := mast.Value(7)
+	assert.Equal(t, 0.0, unknown)
+	assert.Equal(t, uint(0), visits)
+}
+
+func TestTreeWithRolloutPolicy(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations:    200,
+		SimulationConfig: SimulationConfig{Policy: NewMASTPolicy(0.1)},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+}
@@ -0,0 +1,391 @@
+package mcts
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	treeMagic   = "MCTS"
+	treeVersion = uint16(2)
+
+	flagStates  = byte(1)
+	flagActions = byte(2)
+
+	// maxBlobSize and maxIterations bound what Load reads, so corrupt input fails instead of
+	// allocating without limit.
+	maxBlobSize   = 64 << 20
+	maxIterations = 1 << 24
+)
+
+// Codec encodes states and actions when saving a tree. Without a codec only the statistics,
+// IDs and structure are saved, and states are rebuilt on Load by expanding the root state again.
+type Codec interface {
+	EncodeState(s State) ([]byte, error)
+	DecodeState(b []byte) (State, error)
+	EncodeAction(a any) ([]byte, error)
+	DecodeAction(b []byte) (any, error)
+}
+
+// Save writes the tree to w, codec is optional.
+func (mct *MonteCarloTree) Save(w io.Writer, codec Codec) error {
+	if mct.node == nil {
+		return fmt.Errorf("tree has no root, call Start first")
+	}
+	if mct.pondering != nil {
+		return fmt.Errorf("cannot save tree while pondering")
+	}
+	enc := &treeEncoder{w: bufio.NewWriter(w), codec: codec}
+	enc.writeBytes([]byte(treeMagic))
+	enc.writeUint16(treeVersion)
+	flags := byte(0)
+	if codec != nil {
+		flags = flagStates | flagActions
+	}
+	enc.writeBytes([]byte{flags})
+	enc.writeUvarint(uint64(mct.totalInteractions))
+	enc.writeNode(mct.node)
+	if enc.err != nil {
+		return enc.err
+	}
+	return enc.w.Flush()
+}
+
+// Load replaces the tree with the one read from r. Saved states are decoded with codec, otherwise
+// they are rebuilt by expanding root along the saved actions, which only restores the same states
+// when Expand is deterministic. With neither codec nor root, the tree can be inspected but not searched.
+func (mct *MonteCarloTree) Load(r io.Reader, root State, codec Codec) error {
+	if _, err := mct.StopPondering(); err != nil {
+		return err
+	}
+	dec := &treeDecoder{r: bufio.NewReader(r), codec: codec}
+
+	magic := dec.readBytes(len(treeMagic))
+	if dec.err == nil && string(magic) != treeMagic {
+		return fmt.Errorf("invalid tree format")
+	}
+	dec.version = dec.readUint16()
+	// version 1 trees lack the MDP and SP-MCTS statistics, which are loaded as zero
+	if dec.err == nil && (dec.version < 1 || dec.version > treeVersion) {
+		return fmt.Errorf("unsupported tree version %d", dec.version)
+	}
+	flags := dec.readBytes(1)
+	if dec.err != nil {
+		return dec.err
+	}
+	dec.flags = flags[0]
+	if dec.flags&(flagStates|flagActions) != 0 && codec == nil {
+		return fmt.Errorf("tree was saved with a codec, a codec is required to load it")
+	}
+	totalInteractions := dec.readUvarint()
+
+	node := dec.readNode(nil)
+	if dec.err != nil {
+		return dec.err
+	}
+	if node.state == nil && root != nil {
+		node.state = root.Copy()
+		if err := rebuildStates(node); err != nil {
+			return err
+		}
+	}
+
+	mct.node = node
+	mct.totalInteractions = uint(totalInteractions)
+	mct.budget.recount(node)
+	return nil
+}
+
+// rebuildStates expands the states of n children from the saved actions indexes.
+func rebuildStates(n *Node) error {
+	if n.iterations == nil && len(n.child) == 0 {
+		return nil
+	}
+	if n.iterations == nil {
+		n.iterations = n.iterationsOf()
+	}
+	for _, child := range n.child {
+		if child.actionIdx < 0 || child.actionIdx >= len(n.iterations) {
+			return fmt.Errorf("node %s: action %d out of range", child.id, child.actionIdx)
+		}
+		child.action = n.iterations[child.actionIdx]
+		child.state, child.reward = n.expandReward(child.action)
+		if child.state == nil {
+			return fmt.Errorf("expand return nil")
+		}
+		if err := rebuildStates(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type treeEncoder struct {
+	w     *bufio.Writer
+	codec Codec
+	err   error
+}
+
+func (e *treeEncoder) writeBytes(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *treeEncoder) writeUint16(v uint16) {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	e.writeBytes(b)
+}
+
+func (e *treeEncoder) writeUvarint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.writeBytes(b[:binary.PutUvarint(b, v)])
+}
+
+func (e *treeEncoder) writeVarint(v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.writeBytes(b[:binary.PutVarint(b, v)])
+}
+
+func (e *treeEncoder) writeFloat(v float64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, math.Float64bits(v))
+	e.writeBytes(b)
+}
+
+func (e *treeEncoder) writeBool(v bool) {
+	if v {
+		e.writeBytes([]byte{1})
+	} else {
+		e.writeBytes([]byte{0})
+	}
+}
+
+func (e *treeEncoder) writeBlob(b []byte) {
+	e.writeUvarint(uint64(len(b)))
+	e.writeBytes(b)
+}
+
+func (e *treeEncoder) writeNode(n *Node) {
+	e.writeBlob([]byte(n.id))
+	e.writeVarint(int64(n.actionIdx))
+	e.writeBlob([]byte(actionLabel(n.action)))
+	e.writeFloat(n.score)
+	e.writeUvarint(uint64(n.nVisited))
+	e.writeVarint(int64(n.levelY))
+	e.writeUvarint(uint64(n.currIterationIdx))
+	e.writeFloat(n.reward)
+	e.writeFloat(n.value)
+	e.writeBool(n.backed)
+	e.writeFloat(n.sumSquares)
+	e.writeFloat(n.maxScore)
+	if n.iterations == nil {
+		e.writeVarint(-1)
+	} else {
+		e.writeVarint(int64(len(n.iterations)))
+	}
+
+	if e.codec != nil && e.err == nil {
+		stateBytes, err := e.codec.EncodeState(n.state)
+		if err != nil {
+			e.err = err
+			return
+		}
+		e.writeBlob(stateBytes)
+		for _, iter := range n.iterations {
+			actionBytes, err := e.codec.EncodeAction(iter)
+			if err != nil {
+				e.err = err
+				return
+			}
+			e.writeBlob(actionBytes)
+		}
+	}
+
+	e.writeUvarint(uint64(len(n.child)))
+	for _, child := range n.child {
+		e.writeNode(child)
+	}
+}
+
+type treeDecoder struct {
+	r       *bufio.Reader
+	codec   Codec
+	version uint16
+	flags   byte
+	err     error
+}
+
+func (d *treeDecoder) readBytes(size int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	b := make([]byte, size)
+	_, d.err = io.ReadFull(d.r, b)
+	return b
+}
+
+func (d *treeDecoder) readUint16() uint16 {
+	b := d.readBytes(2)
+	if d.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (d *treeDecoder) readUvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *treeDecoder) readVarint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	d.err = err
+	return v
+}
+
+func (d *treeDecoder) readFloat() float64 {
+	b := d.readBytes(8)
+	if d.err != nil {
+		return 0
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b))
+}
+
+func (d *treeDecoder) readBool() bool {
+	b := d.readBytes(1)
+	if d.err != nil {
+		return false
+	}
+	return b[0] != 0
+}
+
+func (d *treeDecoder) readBlob() []byte {
+	size := d.readUvarint()
+	if d.err != nil {
+		return nil
+	}
+	if size > maxBlobSize {
+		d.err = fmt.Errorf("blob of %d bytes exceeds %d", size, maxBlobSize)
+		return nil
+	}
+	// read through a limited reader so a truncated input doesn't allocate the whole size upfront
+	b, err := io.ReadAll(io.LimitReader(d.r, int64(size)))
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	if uint64(len(b)) != size {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	return b
+}
+
+func (d *treeDecoder) readNode(parent *Node) *Node {
+	n := &Node{parent: parent}
+	n.id = string(d.readBlob())
+	n.actionIdx = int(d.readVarint())
+	label := string(d.readBlob())
+	if parent != nil {
+		n.action = label
+	}
+	n.score = d.readFloat()
+	n.nVisited = uint(d.readUvarint())
+	n.levelY = int(d.readVarint())
+	n.currIterationIdx = int(d.readUvarint())
+	if d.version >= 2 {
+		n.reward = d.readFloat()
+		n.value = d.readFloat()
+		n.backed = d.readBool()
+		n.sumSquares = d.readFloat()
+		n.maxScore = d.readFloat()
+	}
+	totalIterations := d.readVarint()
+	if d.err != nil {
+		return nil
+	}
+	if parent != nil && n.actionIdx < 0 {
+		d.err = fmt.Errorf("node %s: invalid action index %d", n.id, n.actionIdx)
+		return nil
+	}
+	if totalIterations < -1 || totalIterations > maxIterations {
+		d.err = fmt.Errorf("node %s: invalid iterations count %d", n.id, totalIterations)
+		return nil
+	}
+	if n.currIterationIdx < 0 || n.currIterationIdx > maxIterations ||
+		(totalIterations >= 0 && int64(n.currIterationIdx) > totalIterations) {
+		d.err = fmt.Errorf("node %s: invalid iteration index %d", n.id, n.currIterationIdx)
+		return nil
+	}
+
+	if d.flags&flagStates != 0 && d.err == nil {
+		state, err := d.codec.DecodeState(d.readBlob())
+		if d.err != nil {
+			return nil
+		}
+		if err != nil {
+			d.err = err
+			return nil
+		}
+		n.state = state
+	}
+	if d.flags&flagActions != 0 && totalIterations >= 0 {
+		n.iterations = make([]any, 0, min(totalIterations, 1024))
+		for i := int64(0); i < totalIterations && d.err == nil; i++ {
+			action, err := d.codec.DecodeAction(d.readBlob())
+			if d.err != nil {
+				return nil
+			}
+			if err != nil {
+				d.err = err
+				return nil
+			}
+			n.iterations = append(n.iterations, action)
+		}
+	}
+
+	totalChild := d.readUvarint()
+	for i := uint64(0); i < totalChild && d.err == nil; i++ {
+		child := d.readNode(n)
+		if d.err != nil {
+			return nil
+		}
+		if totalIterations >= 0 && int64(child.actionIdx) >= totalIterations {
+			d.err = fmt.Errorf("node %s: action %d out of range", child.id, child.actionIdx)
+			return nil
+		}
+		if n.iterations != nil {
+			child.action = n.iterations[child.actionIdx]
+		}
+		n.child = append(n.child, child)
+	}
+	if d.err != nil {
+		return nil
+	}
+	// every tried iteration has a child, which the rebuilt iterations must match
+	if n.iterations == nil && n.currIterationIdx != len(n.child) {
+		d.err = fmt.Errorf("node %s: %d iterations tried for %d children", n.id, n.currIterationIdx, len(n.child))
+		return nil
+	}
+	return n
+}
+
+func actionLabel(action any) string {
+	if action == nil {
+		return ""
+	}
+	return fmt.Sprint(action)
+}
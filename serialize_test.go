@@ -0,0 +1,246 @@
+package mcts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type sumCodec struct{}
+
+func (sumCodec) EncodeState(s State) ([]byte, error) {
+	return json.Marshal(s.(sumState).total)
+}
+
+func (sumCodec) DecodeState(b []byte) (State, error) {
+	state := newSumState()
+	err := json.Unmarshal(b, &state.total)
+	return state, err
+}
+
+func (sumCodec) EncodeAction(a any) ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (sumCodec) DecodeAction(b []byte) (any, error) {
+	var action int
+	err := json.Unmarshal(b, &action)
+	return action, err
+}
+
+func assertSameTree(t *testing.T, expected, actual *Node) {
+	assert.Equal(t, expected.id, actual.id)
+	assert.Equal(t, expected.score, actual.score)
+	assert.Equal(t, expected.nVisited, actual.nVisited)
+	assert.Equal(t, expected.levelY, actual.levelY)
+	assert.Equal(t, expected.currIterationIdx, actual.currIterationIdx)
+	assert.Equal(t, expected.actionIdx, actual.actionIdx)
+	assert.Equal(t, expected.reward, actual.reward)
+	assert.Equal(t, expected.value, actual.value)
+	assert.Equal(t, expected.backed, actual.backed)
+	assert.Equal(t, expected.sumSquares, actual.sumSquares)
+	assert.Equal(t, expected.maxScore, actual.maxScore)
+	assert.Len(t, actual.child, len(expected.child))
+	for i := range expected.child {
+		assert.Equal(t, actual, actual.child[i].parent)
+		assertSameTree(t, expected.child[i], actual.child[i])
+	}
+}
+
+func TestSaveLoadReplay(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 200})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, nil))
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 100})
+	assert.NoError(t, loaded.Load(buf, newSumState(), nil))
+	assertSameTree(t, tree.node, loaded.node)
+	assert.Equal(t, tree.node.child[1].state, loaded.node.child[1].state)
+	assert.Equal(t, tree.node.child[1].action, loaded.node.child[1].action)
+	assert.Equal(t, tree.budget.nodes, loaded.budget.nodes)
+
+	res, err := loaded.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(300), res.Iterations)
+	assert.Equal(t, 5, res.NodeScore[0].State.(sumState).total)
+}
+
+func TestSaveLoadCodec(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 200})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, sumCodec{}))
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 100})
+	assert.Error(t, loaded.Load(bytes.NewReader(buf.Bytes()), nil, nil))
+	assert.NoError(t, loaded.Load(bytes.NewReader(buf.Bytes()), nil, sumCodec{}))
+	assertSameTree(t, tree.node, loaded.node)
+	assert.Equal(t, tree.node.child[2].state.(sumState).total, loaded.node.child[2].state.(sumState).total)
+	assert.Equal(t, tree.node.iterations, loaded.node.iterations)
+	assert.Equal(t, tree.node.child[2].action, loaded.node.child[2].action)
+
+	_, err = loaded.Continue()
+	assert.NoError(t, err)
+}
+
+type delayedCodec struct{}
+
+func (delayedCodec) EncodeState(s State) ([]byte, error) {
+	return []byte(s.(delayedState).path), nil
+}
+
+func (delayedCodec) DecodeState(b []byte) (State, error) {
+	return delayedState{path: string(b)}, nil
+}
+
+func (delayedCodec) EncodeAction(a any) ([]byte, error) {
+	return []byte(a.(string)), nil
+}
+
+func (delayedCodec) DecodeAction(b []byte) (any, error) {
+	return string(b), nil
+}
+
+func TestSaveLoadMDP(t *testing.T) {
+	mdp := MDPConfig{Enabled: true, Gamma: 0.5, Backup: MaxBackup}
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50, MDP: mdp})
+	_, err := tree.Start(delayedState{})
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, delayedCodec{}))
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50, MDP: mdp})
+	assert.NoError(t, loaded.Load(buf, nil, delayedCodec{}))
+	assertSameTree(t, tree.node, loaded.node)
+
+	// both trees back up the same returns from now on
+	_, err = tree.Continue()
+	assert.NoError(t, err)
+	res, err := loaded.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, "later", res.NodeScore[0].Action)
+	assert.Equal(t, tree.node.value, loaded.node.value)
+}
+
+func TestSaveLoadSinglePlayer(t *testing.T) {
+	config := MonteCarloTreeConfig{MaxIterations: 100, SinglePlayer: SinglePlayerConfig{Enabled: true, D: 1}}
+	tree := NewMonteCarloTree(config)
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, sumCodec{}))
+
+	loaded := NewMonteCarloTree(config)
+	assert.NoError(t, loaded.Load(buf, nil, sumCodec{}))
+	assertSameTree(t, tree.node, loaded.node)
+	assert.Equal(t, tree.node.MaxScore(), loaded.node.MaxScore())
+	assert.Equal(t, tree.node.Variance(), loaded.node.Variance())
+}
+
+func TestLoadVersion1(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := &treeEncoder{w: bufio.NewWriter(buf)}
+	enc.writeBytes([]byte(treeMagic))
+	enc.writeUint16(1)
+	enc.writeBytes([]byte{0})
+	enc.writeUvarint(3)
+	// a root without the MDP and SP-MCTS statistics of version 2
+	enc.writeBlob([]byte("0-0"))
+	enc.writeVarint(0)
+	enc.writeBlob(nil)
+	enc.writeFloat(1.5)
+	enc.writeUvarint(3)
+	enc.writeVarint(0)
+	enc.writeUvarint(0)
+	enc.writeVarint(-1)
+	enc.writeUvarint(0)
+	assert.NoError(t, enc.w.Flush())
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{})
+	assert.NoError(t, loaded.Load(buf, nil, nil))
+	assert.Equal(t, 1.5, loaded.node.score)
+	assert.Equal(t, uint(3), loaded.node.nVisited)
+	assert.False(t, loaded.node.backed)
+}
+
+func TestLoadWithoutStates(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, nil))
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{})
+	assert.NoError(t, loaded.Load(buf, nil, nil))
+	assertSameTree(t, tree.node, loaded.node)
+	assert.Equal(t, "5", loaded.node.child[1].action)
+	_, err = loaded.Continue()
+	assert.Error(t, err)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{})
+	assert.Error(t, tree.Load(bytes.NewReader([]byte("nope")), nil, nil))
+	assert.Error(t, tree.Load(bytes.NewReader([]byte("MCTS\x00\x09\x00")), nil, nil))
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, tree.Save(buf, sumCodec{}))
+	saved := buf.Bytes()
+
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{})
+	for size := 0; size < len(saved); size++ {
+		assert.Error(t, loaded.Load(bytes.NewReader(saved[:size]), nil, sumCodec{}), "truncated at %d", size)
+	}
+
+	// the id of the root claims a huge length
+	huge := []byte("MCTS\x00\x01\x00\x00\xff\xff\xff\xff\xff\xff\xff\xff\x7f")
+	huge[5] = byte(treeVersion)
+	assert.Error(t, loaded.Load(bytes.NewReader(huge), nil, nil))
+
+	tree.node.child[0].actionIdx = -1
+	buf.Reset()
+	assert.NoError(t, tree.Save(buf, nil))
+	assert.Error(t, loaded.Load(buf, newSumState(), nil))
+}
+
+func TestLoadInconsistentIterations(t *testing.T) {
+	saveCorrupt := func(codec Codec, corrupt func(n *Node)) *bytes.Buffer {
+		tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+		_, err := tree.Start(newSumState())
+		assert.NoError(t, err)
+		corrupt(tree.node)
+		buf := &bytes.Buffer{}
+		assert.NoError(t, tree.Save(buf, codec))
+		return buf
+	}
+	loaded := NewMonteCarloTree(MonteCarloTreeConfig{})
+
+	// more iterations tried than the node has
+	buf := saveCorrupt(sumCodec{}, func(n *Node) { n.currIterationIdx = len(n.iterations) + 1 })
+	assert.Error(t, loaded.Load(buf, nil, sumCodec{}))
+
+	// without saved iterations, the tried ones must match the children
+	buf = saveCorrupt(nil, func(n *Node) { n.currIterationIdx = len(n.child) + 1 })
+	assert.Error(t, loaded.Load(buf, nil, nil))
+	buf = saveCorrupt(nil, func(n *Node) { n.child[0].currIterationIdx = len(n.child[0].child) + 1 })
+	assert.Error(t, loaded.Load(buf, newSumState(), nil))
+
+	// a child whose action is not among the saved iterations
+	buf = saveCorrupt(sumCodec{}, func(n *Node) { n.child[0].actionIdx = len(n.iterations) })
+	assert.Error(t, loaded.Load(buf, nil, sumCodec{}))
+}
@@ -0,0 +1,384 @@
+package mcts
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SimultaneousState is a game where all players choose their action at once.
+type SimultaneousState interface {
+	// Players returns how many players choose an action on every turn.
+	Players() int
+	// PlayerActions returns the actions available to player, the game is over when any player has none.
+	PlayerActions(player int) []any
+	// ExpandJoint plays the joint action holding one action per player.
+	ExpandJoint(actions []any) SimultaneousState
+	// Simulate plays a random game from the state and returns the score of every player.
+	Simulate() []float64
+	Copy() SimultaneousState
+	ID() string
+}
+
+// SimultaneousSelection is how every player chooses its action in a simultaneous node.
+type SimultaneousSelection string
+
+const (
+	// DecoupledUCT picks the action with the best UCB of each player, ignoring the others.
+	DecoupledUCT SimultaneousSelection = "duct"
+	// EXP3 samples the actions from exponential weights of their estimated rewards.
+	EXP3 SimultaneousSelection = "exp3"
+	// RegretMatching samples the actions proportionally to their positive regrets.
+	RegretMatching SimultaneousSelection = "rm"
+)
+
+// SimultaneousConfig configures a SimultaneousTree.
+type SimultaneousConfig struct {
+	MaxTimeout    *time.Duration
+	MaxIterations uint
+	// Selection is DecoupledUCT by default.
+	Selection SimultaneousSelection
+	// C weighs the exploration of DecoupledUCT, sqrt(2) by default.
+	C float64
+	// Gamma is the exploration rate of EXP3 and RegretMatching, 0.1 by default.
+	Gamma float64
+	// MinScore and MaxScore bound the scores of Simulate, -1 and 1 by default.
+	// EXP3 and RegretMatching rescale the scores to [0, 1] with them.
+	MinScore float64
+	MaxScore float64
+}
+
+// SimultaneousTree searches simultaneous move games, every node keeps the statistics of each player
+// apart and its children are the joint actions played from it.
+type SimultaneousTree struct {
+	config SimultaneousConfig
+	root   *simNode
+	nodes  uint
+}
+
+// SimultaneousScore is the result of a SimultaneousTree search.
+type SimultaneousScore struct {
+	Iterations uint
+	// Strategies holds the mixed strategy of every player at the root.
+	Strategies [][]ActionProbability
+	TotalNodes uint
+	StopReason StopReason
+}
+
+// ActionProbability is the probability of an action in a mixed strategy.
+type ActionProbability struct {
+	Action      any
+	Probability float64
+	Visits      uint
+	// Value is the mean score of the player when playing Action.
+	Value float64
+}
+
+type simNode struct {
+	state    SimultaneousState
+	visits   uint
+	players  []*playerStats
+	children map[string]*simNode
+}
+
+type playerStats struct {
+	actions []any
+	visits  []uint
+	totals  []float64
+	// weights are the EXP3 estimated rewards or the RegretMatching regrets.
+	weights []float64
+	// strategySum accumulates the RegretMatching strategies, its average is the output strategy.
+	strategySum []float64
+}
+
+// NewSimultaneousTree creates a SimultaneousTree.
+func NewSimultaneousTree(config SimultaneousConfig) *SimultaneousTree {
+	if config.MaxIterations == 0 && config.MaxTimeout == nil {
+		config.MaxIterations = 1000
+	}
+	if config.Selection == "" {
+		config.Selection = DecoupledUCT
+	}
+	if config.C <= 0 {
+		config.C = math.Sqrt2
+	}
+	if config.Gamma <= 0 || config.Gamma > 1 {
+		config.Gamma = 0.1
+	}
+	if config.MinScore == 0 && config.MaxScore == 0 {
+		config.MinScore, config.MaxScore = -1, 1
+	}
+	return &SimultaneousTree{config: config}
+}
+
+// Start searches from initialState.
+func (st *SimultaneousTree) Start(initialState SimultaneousState) (SimultaneousScore, error) {
+	st.root = newSimNode(initialState.Copy())
+	st.nodes = 1
+	if st.root.terminal() {
+		return SimultaneousScore{}, fmt.Errorf("game is over")
+	}
+
+	started := time.Now()
+	iterations := uint(0)
+	stopReason := StopMaxIterations
+	for {
+		if err := st.iterate(); err != nil {
+			return SimultaneousScore{}, err
+		}
+		iterations++
+		if st.config.MaxIterations > 0 && iterations >= st.config.MaxIterations {
+			break
+		}
+		if st.config.MaxTimeout != nil && time.Since(started) >= *st.config.MaxTimeout {
+			stopReason = StopTimeout
+			break
+		}
+	}
+
+	strategies := make([][]ActionProbability, len(st.root.players))
+	for player, stats := range st.root.players {
+		strategies[player] = st.strategy(stats)
+	}
+	return SimultaneousScore{
+		Iterations: iterations,
+		Strategies: strategies,
+		TotalNodes: st.nodes,
+		StopReason: stopReason,
+	}, nil
+}
+
+func newSimNode(state SimultaneousState) *simNode {
+	n := &simNode{state: state, children: make(map[string]*simNode)}
+	for player := 0; player < state.Players(); player++ {
+		actions := state.PlayerActions(player)
+		n.players = append(n.players, &playerStats{
+			actions:     actions,
+			visits:      make([]uint, len(actions)),
+			totals:      make([]float64, len(actions)),
+			weights:     make([]float64, len(actions)),
+			strategySum: make([]float64, len(actions)),
+		})
+	}
+	return n
+}
+
+func (n *simNode) terminal() bool {
+	for _, stats := range n.players {
+		if len(stats.actions) == 0 {
+			return true
+		}
+	}
+	return len(n.players) == 0
+}
+
+// simStep is a joint action chosen on the way down, with the probability of every chosen action
+// and the mixed strategies they were sampled from.
+type simStep struct {
+	node    *simNode
+	chosen  []int
+	probs   []float64
+	mixture [][]float64
+}
+
+// iterate descends the tree with joint actions until it adds a node or reaches the end of the game,
+// then backpropagates the scores of the simulation to every player.
+func (st *SimultaneousTree) iterate() error {
+	path := make([]simStep, 0)
+	n := st.root
+	var scores []float64
+	for {
+		if n.terminal() {
+			scores = n.state.Copy().Simulate()
+			break
+		}
+		step := simStep{node: n}
+		joint := make([]any, len(n.players))
+		for player, stats := range n.players {
+			idx, prob, mixture := st.choose(n, stats)
+			step.chosen = append(step.chosen, idx)
+			step.probs = append(step.probs, prob)
+			step.mixture = append(step.mixture, mixture)
+			joint[player] = stats.actions[idx]
+		}
+		path = append(path, step)
+
+		key := jointKey(joint)
+		child, ok := n.children[key]
+		if !ok {
+			state := n.state.Copy().ExpandJoint(joint)
+			if state == nil {
+				return fmt.Errorf("expand return nil")
+			}
+			child = newSimNode(state)
+			n.children[key] = child
+			st.nodes++
+			n = child
+			scores = state.Copy().Simulate()
+			break
+		}
+		n = child
+	}
+
+	// the leaf, expanded or terminal, isn't on the path
+	n.visits++
+	for _, step := range path {
+		step.node.visits++
+		for player, stats := range step.node.players {
+			if player >= len(scores) {
+				return fmt.Errorf("simulate returned %d scores for %d players", len(scores), len(step.node.players))
+			}
+			st.update(stats, step, player, scores[player])
+		}
+	}
+	return nil
+}
+
+// choose returns the action index of a player, its probability and the mixed strategy it was sampled from.
+func (st *SimultaneousTree) choose(n *simNode, stats *playerStats) (int, float64, []float64) {
+	switch st.config.Selection {
+	case EXP3:
+		mixture := st.exp3Strategy(stats)
+		idx := sample(mixture)
+		return idx, mixture[idx], mixture
+	case RegretMatching:
+		mixture := st.regretStrategy(stats)
+		idx := sample(mixture)
+		return idx, mixture[idx], mixture
+	}
+	best, bestScore := 0, math.Inf(-1)
+	for idx := range stats.actions {
+		if stats.visits[idx] == 0 {
+			return idx, 1, nil
+		}
+		mean := stats.totals[idx] / float64(stats.visits[idx])
+		score := mean + st.config.C*math.Sqrt(math.Log(float64(n.visits))/float64(stats.visits[idx]))
+		if score > bestScore {
+			best, bestScore = idx, score
+		}
+	}
+	return best, 1, nil
+}
+
+func (st *SimultaneousTree) update(stats *playerStats, step simStep, player int, score float64) {
+	idx := step.chosen[player]
+	stats.visits[idx]++
+	stats.totals[idx] += score
+
+	reward := (score - st.config.MinScore) / (st.config.MaxScore - st.config.MinScore)
+	switch st.config.Selection {
+	case EXP3:
+		stats.weights[idx] += reward / step.probs[player]
+	case RegretMatching:
+		for other := range stats.weights {
+			estimate := 0.0
+			if other == idx {
+				estimate = reward / step.probs[player]
+			}
+			stats.weights[other] += estimate - reward
+		}
+		for other, prob := range step.mixture[player] {
+			stats.strategySum[other] += prob
+		}
+	}
+}
+
+func (st *SimultaneousTree) exp3Strategy(stats *playerStats) []float64 {
+	k := float64(len(stats.actions))
+	eta := st.config.Gamma / k
+	max := math.Inf(-1)
+	for _, weight := range stats.weights {
+		max = math.Max(max, weight)
+	}
+	mixture := make([]float64, len(stats.actions))
+	total := 0.0
+	for idx, weight := range stats.weights {
+		// shifted by max to avoid overflows
+		mixture[idx] = math.Exp(eta * (weight - max))
+		total += mixture[idx]
+	}
+	for idx := range mixture {
+		mixture[idx] = (1-st.config.Gamma)*mixture[idx]/total + st.config.Gamma/k
+	}
+	return mixture
+}
+
+func (st *SimultaneousTree) regretStrategy(stats *playerStats) []float64 {
+	k := float64(len(stats.actions))
+	mixture := make([]float64, len(stats.actions))
+	total := 0.0
+	for idx, regret := range stats.weights {
+		mixture[idx] = math.Max(0, regret)
+		total += mixture[idx]
+	}
+	for idx := range mixture {
+		if total > 0 {
+			mixture[idx] = (1-st.config.Gamma)*mixture[idx]/total + st.config.Gamma/k
+		} else {
+			mixture[idx] = 1 / k
+		}
+	}
+	return mixture
+}
+
+// strategy is the mixed strategy of a player at the root: the average strategy for RegretMatching
+// and the visit frequencies otherwise.
+func (st *SimultaneousTree) strategy(stats *playerStats) []ActionProbability {
+	weights := make([]float64, len(stats.actions))
+	total := 0.0
+	for idx := range stats.actions {
+		weights[idx] = float64(stats.visits[idx])
+		if st.config.Selection == RegretMatching {
+			weights[idx] = stats.strategySum[idx]
+		}
+		total += weights[idx]
+	}
+	strategy := make([]ActionProbability, len(stats.actions))
+	for idx, action := range stats.actions {
+		strategy[idx] = ActionProbability{Action: action, Visits: stats.visits[idx]}
+		if total > 0 {
+			strategy[idx].Probability = weights[idx] / total
+		}
+		if stats.visits[idx] > 0 {
+			strategy[idx].Value = stats.totals[idx] / float64(stats.visits[idx])
+		}
+	}
+	sort.SliceStable(strategy, func(i, j int) bool {
+		return strategy[i].Probability > strategy[j].Probability
+	})
+	return strategy
+}
+
+// SampleStrategy picks an action of a mixed strategy according to its probabilities.
+func SampleStrategy(strategy []ActionProbability) any {
+	if len(strategy) == 0 {
+		return nil
+	}
+	probs := make([]float64, len(strategy))
+	for idx, action := range strategy {
+		probs[idx] = action.Probability
+	}
+	return strategy[sample(probs)].Action
+}
+
+func sample(probs []float64) int {
+	pick := rand.Float64()
+	for idx, prob := range probs {
+		pick -= prob
+		if pick < 0 {
+			return idx
+		}
+	}
+	return len(probs) - 1
+}
+
+func jointKey(actions []any) string {
+	labels := make([]string, len(actions))
+	for idx, action := range actions {
+		labels[idx] = actionLabel(action)
+	}
+	return strings.Join(labels, "|")
+}
@@ -0,0 +1,88 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// matrixGame is a one shot game, payoff[a][b] is the score of player 0, player 1 gets the opposite.
+type matrixGame struct {
+	payoff [][]float64
+	played []any
+}
+
+func (m matrixGame) Players() int {
+	return 2
+}
+
+func (m matrixGame) PlayerActions(player int) []any {
+	if m.played != nil {
+		return nil
+	}
+	size := len(m.payoff)
+	if player == 1 {
+		size = len(m.payoff[0])
+	}
+	actions := make([]any, size)
+	for idx := range actions {
+		actions[idx] = idx
+	}
+	return actions
+}
+
+func (m matrixGame) ExpandJoint(actions []any) SimultaneousState {
+	return matrixGame{payoff: m.payoff, played: actions}
+}
+
+func (m matrixGame) Simulate() []float64 {
+	score := m.payoff[m.played[0].(int)][m.played[1].(int)]
+	return []float64{score, -score}
+}
+
+func (m matrixGame) Copy() SimultaneousState {
+	return m
+}
+
+func (m matrixGame) ID() string {
+	return jointKey(m.played)
+}
+
+func TestSimultaneousDominantStrategy(t *testing.T) {
+	// action 1 dominates for both players
+	game := matrixGame{payoff: [][]float64{{0, -1}, {1, 0.5}}}
+	for _, selection := range []SimultaneousSelection{DecoupledUCT, EXP3, RegretMatching} {
+		rand.Seed(1)
+		tree := NewSimultaneousTree(SimultaneousConfig{MaxIterations: 3000, Selection: selection})
+		res, err := tree.Start(game)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Strategies[0][0].Action, selection)
+		assert.Equal(t, 1, res.Strategies[1][0].Action, selection)
+		assert.Greater(t, res.Strategies[0][0].Probability, 0.7, selection)
+		assert.Equal(t, uint(5), res.TotalNodes, selection)
+	}
+}
+
+func TestSimultaneousMixedStrategy(t *testing.T) {
+	rand.Seed(1)
+	// matching pennies, both players should mix evenly
+	game := matrixGame{payoff: [][]float64{{1, -1}, {-1, 1}}}
+	tree := NewSimultaneousTree(SimultaneousConfig{MaxIterations: 20000, Selection: RegretMatching})
+	res, err := tree.Start(game)
+	assert.NoError(t, err)
+	for _, strategy := range res.Strategies {
+		assert.Len(t, strategy, 2)
+		assert.InDelta(t, 0.5, strategy[0].Probability, 0.1)
+		assert.InDelta(t, 0.5, strategy[1].Probability, 0.1)
+	}
+
+	action := SampleStrategy(res.Strategies[0])
+	assert.Contains(t, []any{0, 1}, action)
+	assert.Nil(t, SampleStrategy(nil))
+}
+
+func TestSimultaneousGameOver(t *testing.T) {
+	tree := NewSimultaneousTree(SimultaneousConfig{})
+	_, err := tree.Start(matrixGame{payoff: [][]float64{{0}}, played: []any{0, 0}})
+	assert.Error(t, err)
+}
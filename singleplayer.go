@@ -0,0 +1,91 @@
+package mcts
+
+import "math"
+
+// SinglePlayerConfig enables SP-MCTS for puzzles, where the best sequence found matters more than averages.
+// Children are selected by mean + C * sqrt(ln N / n) + sqrt((sum of squares - n * mean² + D) / n),
+// so promising nodes with a high variance keep being explored.
+type SinglePlayerConfig struct {
+	Enabled bool
+	// C weighs the exploration term, sqrt(2) by default.
+	C float64
+	// D is added to the variance term, so rarely visited nodes still look uncertain.
+	D float64
+}
+
+func (s SinglePlayerConfig) c() float64 {
+	if s.C <= 0 {
+		return math.Sqrt2
+	}
+	return s.C
+}
+
+// selector scores the children of a node during selection, the highest is selected.
+type selector interface {
+	score(child *Node) float64
+}
+
+func (p PolicyFunc) score(child *Node) float64 {
+	return p(child.selectionTotal(), child.nVisited, child.parent.nVisited)
+}
+
+type spSelector struct {
+	config SinglePlayerConfig
+}
+
+func (s spSelector) score(child *Node) float64 {
+	if child.nVisited == 0 {
+		return math.Inf(1)
+	}
+	n := float64(child.nVisited)
+	mean := child.selectionTotal() / n
+	exploration := s.config.c() * math.Sqrt(math.Log(float64(child.parent.nVisited))/n)
+	rawMean := child.score / n
+	variance := math.Max(0, (child.sumSquares-n*rawMean*rawMean+s.config.D)/n)
+	return mean + exploration + math.Sqrt(variance)
+}
+
+func (mct *MonteCarloTree) selector() selector {
+	if mct.singlePlayer.Enabled {
+		return spSelector{config: mct.singlePlayer}
+	}
+	return mct.policy
+}
+
+// bestSequence is the best rollout of a search.
+type bestSequence struct {
+	found   bool
+	score   float64
+	actions []any
+}
+
+// record keeps the actions from the root down to n followed by the rollout ones when score is the best.
+func (b *bestSequence) record(root, n *Node, played []any, score float64) {
+	if b.found && score <= b.score {
+		return
+	}
+	path := make([]any, 0, n.levelY-root.levelY+len(played))
+	for curr := n; curr != nil && curr != root; curr = curr.parent {
+		path = append(path, curr.action)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	b.found = true
+	b.score = score
+	b.actions = append(path, played...)
+}
+
+// MaxScore returns the highest score backpropagated through the node.
+func (n *Node) MaxScore() float64 {
+	return n.maxScore
+}
+
+// Variance returns the variance of the scores backpropagated through the node.
+func (n *Node) Variance() float64 {
+	if n.nVisited == 0 {
+		return 0
+	}
+	mean := n.meanScore()
+	return math.Max(0, n.sumSquares/float64(n.nVisited)-mean*mean)
+}
@@ -0,0 +1,68 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNodeScoreStatistics(t *testing.T) {
+	n := &Node{}
+	n.visit(-1)
+	n.visit(3)
+	n.visit(1)
+	assert.Equal(t, 3.0, n.MaxScore())
+	assert.Equal(t, 11.0, n.sumSquares)
+	assert.InDelta(t, 8.0/3, n.Variance(), 1e-9)
+
+	first := &Node{}
+	first.visit(-2)
+	assert.Equal(t, -2.0, first.MaxScore())
+}
+
+func TestSPSelector(t *testing.T) {
+	parent := &Node{nVisited: 10}
+	child := &Node{parent: parent, score: 4, nVisited: 2, sumSquares: 10}
+	sp := spSelector{config: SinglePlayerConfig{Enabled: true, C: 1, D: 2}}
+
+	// 2 + sqrt(ln 10 / 2) + sqrt((10 - 8 + 2) / 2)
+	assert.InDelta(t, 2+math.Sqrt(math.Log(10)/2)+math.Sqrt(2), sp.score(child), 1e-9)
+	assert.True(t, math.IsInf(sp.score(&Node{parent: parent}), 1))
+}
+
+func TestSinglePlayerBestSequence(t *testing.T) {
+	rand.Seed(1)
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations:    300,
+		SinglePlayer:     SinglePlayerConfig{Enabled: true, C: 0.5},
+		SimulationConfig: SimulationConfig{Policy: RandomPolicy{}},
+	})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Equal(t, []any{5, 5, 5, 5}, res.BestSequence)
+	assert.Equal(t, 0.2, res.BestScore)
+	assert.Equal(t, 0.2, tree.node.MaxScore())
+
+	// with several simulations per rollout the best one is kept, not their average
+	rand.Seed(2)
+	ratio := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations:    10,
+		SinglePlayer:     SinglePlayerConfig{Enabled: true},
+		SimulationConfig: SimulationConfig{Policy: RandomPolicy{}, Ratio: 4, Strategy: Avg},
+	})
+	res, err = ratio.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Len(t, res.BestSequence, 4)
+	var state State = newSumState()
+	for _, action := range res.BestSequence {
+		state = state.Expand(action)
+	}
+	assert.Equal(t, state.Simulate(), res.BestScore)
+
+	// searches without SinglePlayer don't record it
+	plain := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 50})
+	res, err = plain.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Nil(t, res.BestSequence)
+}
@@ -0,0 +1,100 @@
+package mcts
+
+import (
+	"time"
+)
+
+// SearchStats describes the tree and how the time of a search was spent.
+type SearchStats struct {
+	// MaxDepth and MeanDepth are measured on the leaves, relative to the root.
+	MaxDepth  int
+	MeanDepth float64
+	// DepthHistogram holds how many nodes there are at each depth.
+	DepthHistogram []uint
+	// BranchingFactor holds the mean number of children of expanded nodes at each depth.
+	BranchingFactor []float64
+
+	Iterations uint
+	// Rollouts is how many nodes were simulated, each running SimulationConfig.Ratio simulations.
+	Rollouts            uint
+	AvgRolloutCost      time.Duration
+	Duration            time.Duration
+	IterationsPerSecond float64
+
+	Selection       time.Duration
+	Expansion       time.Duration
+	Simulation      time.Duration
+	BackPropagation time.Duration
+
+	TotalNodes uint
+	// MemoryEstimate is the approximate tree size in bytes, see MaxMemory.
+	MemoryEstimate uint64
+}
+
+type searchTimings struct {
+	selection       time.Duration
+	expansion       time.Duration
+	simulation      time.Duration
+	backPropagation time.Duration
+	rollouts        uint
+}
+
+func (mct *MonteCarloTree) stats(iterations uint, duration time.Duration) SearchStats {
+	stats := SearchStats{
+		Iterations:      iterations,
+		Rollouts:        mct.timings.rollouts,
+		Duration:        duration,
+		Selection:       mct.timings.selection,
+		Expansion:       mct.timings.expansion,
+		Simulation:      mct.timings.simulation,
+		BackPropagation: mct.timings.backPropagation,
+		TotalNodes:      mct.budget.nodes,
+		MemoryEstimate:  mct.budget.memory,
+	}
+	if stats.Rollouts > 0 {
+		stats.AvgRolloutCost = stats.Simulation / time.Duration(stats.Rollouts)
+	}
+	if duration > 0 {
+		stats.IterationsPerSecond = float64(iterations) / duration.Seconds()
+	}
+
+	children := make([]uint, 0)
+	expanded := make([]uint, 0)
+	leaves := uint(0)
+	leavesDepth := 0
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		depth := n.levelY - mct.node.levelY
+		if len(stats.DepthHistogram) <= depth {
+			stats.DepthHistogram = append(stats.DepthHistogram, 0)
+			children = append(children, 0)
+			expanded = append(expanded, 0)
+		}
+		stats.DepthHistogram[depth]++
+		if len(n.child) == 0 {
+			leaves++
+			leavesDepth += depth
+			if depth > stats.MaxDepth {
+				stats.MaxDepth = depth
+			}
+			return
+		}
+		expanded[depth]++
+		children[depth] += uint(len(n.child))
+		for _, child := range n.child {
+			walk(child)
+		}
+	}
+	walk(mct.node)
+
+	if leaves > 0 {
+		stats.MeanDepth = float64(leavesDepth) / float64(leaves)
+	}
+	for depth := range expanded {
+		if expanded[depth] == 0 {
+			break
+		}
+		stats.BranchingFactor = append(stats.BranchingFactor, float64(children[depth])/float64(expanded[depth]))
+	}
+	return stats
+}
@@ -0,0 +1,39 @@
+package mcts
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSearchStats(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 500})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	stats := res.Stats
+	assert.Equal(t, uint(120), res.TotalNodes)
+	assert.Equal(t, uint(121), stats.TotalNodes)
+	assert.Equal(t, 4, stats.MaxDepth)
+	assert.Equal(t, 4.0, stats.MeanDepth)
+	assert.Equal(t, []uint{1, 3, 9, 27, 81}, stats.DepthHistogram)
+	assert.Equal(t, []float64{3, 3, 3, 3}, stats.BranchingFactor)
+	assert.Equal(t, uint(500), stats.Iterations)
+	assert.Equal(t, uint(500), stats.Rollouts)
+	assert.Greater(t, stats.IterationsPerSecond, 0.0)
+	assert.Equal(t, stats.Simulation/500, stats.AvgRolloutCost)
+	assert.Equal(t, 121*nodeOverhead, stats.MemoryEstimate)
+	assert.LessOrEqual(t, stats.Selection+stats.Expansion+stats.Simulation+stats.BackPropagation, stats.Duration)
+}
+
+func TestSearchStatsAfterAdvance(t *testing.T) {
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 500})
+	res, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.NoError(t, tree.Advance(res.NodeScore[0].State))
+
+	res, err = tree.Continue()
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), res.TotalNodes)
+	assert.Equal(t, []uint{1, 3, 9, 27}, res.Stats.DepthHistogram)
+	assert.Equal(t, 3, res.Stats.MaxDepth)
+}
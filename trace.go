@@ -0,0 +1,59 @@
+package mcts
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// TraceConfig logs the iterations of the search at debug level.
+type TraceConfig struct {
+	Logger *slog.Logger
+	// Every logs one of each Every iterations, zero or one logs all of them.
+	Every uint
+}
+
+type tracer struct {
+	config     TraceConfig
+	iterations uint
+}
+
+// trace logs the iteration that rolled out n with score, when it's sampled.
+func (mct *MonteCarloTree) trace(n *Node, expanded bool, score float64) {
+	t := &mct.tracer
+	if t.config.Logger == nil {
+		return
+	}
+	t.iterations++
+	if t.config.Every > 1 && t.iterations%t.config.Every != 0 {
+		return
+	}
+	ctx := context.Background()
+	if !t.config.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	path := make([]string, 0, n.levelY-mct.node.levelY+1)
+	for curr := n; curr != nil; curr = curr.parent {
+		path = append(path, curr.id)
+		if curr == mct.node {
+			break
+		}
+	}
+	slices.Reverse(path)
+
+	attrs := []slog.Attr{
+		slog.Uint64("iteration", uint64(t.iterations)),
+		slog.Any("path", path),
+		slog.Bool("expanded", expanded),
+		slog.Float64("rollout", score),
+		slog.Uint64("node_visits", uint64(n.nVisited)),
+		slog.Float64("node_score", n.score),
+		slog.Uint64("root_visits", uint64(mct.node.nVisited)),
+		slog.Float64("root_score", mct.node.score),
+	}
+	if expanded {
+		attrs = append(attrs, slog.String("action", actionLabel(n.action)))
+	}
+	t.config.Logger.LogAttrs(ctx, slog.LevelDebug, "mcts iteration", attrs...)
+}
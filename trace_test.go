@@ -0,0 +1,61 @@
+package mcts
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func tracedLines(t *testing.T, every uint, iterations uint) []map[string]any {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{
+		MaxIterations: iterations,
+		Trace:         TraceConfig{Logger: logger, Every: every},
+	})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+
+	lines := make([]map[string]any, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestTrace(t *testing.T) {
+	lines := tracedLines(t, 0, 5)
+	assert.Len(t, lines, 5)
+
+	first := lines[0]
+	assert.Equal(t, "mcts iteration", first["msg"])
+	assert.Equal(t, "DEBUG", first["level"])
+	assert.Equal(t, []any{"0-0", "1-1"}, first["path"])
+	assert.Equal(t, true, first["expanded"])
+	assert.Equal(t, "1", first["action"])
+	assert.Equal(t, 0.1, first["rollout"])
+	assert.Equal(t, 1.0, first["root_visits"])
+	assert.Equal(t, 0.1, first["root_score"])
+}
+
+func TestTraceSampling(t *testing.T) {
+	lines := tracedLines(t, 10, 100)
+	assert.Len(t, lines, 10)
+	assert.Equal(t, 10.0, lines[0]["iteration"])
+	assert.Equal(t, 100.0, lines[9]["iteration"])
+}
+
+func TestTraceDisabledLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	tree := NewMonteCarloTree(MonteCarloTreeConfig{MaxIterations: 20, Trace: TraceConfig{Logger: logger}})
+	_, err := tree.Start(newSumState())
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+	assert.Equal(t, uint(20), tree.tracer.iterations)
+}